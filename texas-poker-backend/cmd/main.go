@@ -5,7 +5,6 @@ package main
 
 import (
 	"log"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
 	
@@ -83,6 +82,22 @@ func setupRoutes(r *gin.Engine, h *handlers.Handler) {
 			rooms.POST("/:id/leave", h.LeaveRoom)
 		}
 		
+		// 公平性验证路由（复核结论公开，无需登录；查看座位底牌需要登录）
+		api.GET("/fairness/games/:id", h.VerifyGame)
+		
+		// 工具路由
+		tools := api.Group("/tools", middleware.AuthRequired())
+		{
+			tools.POST("/equity", h.CalculateEquity)
+		}
+		
+		// 牌局历史路由
+		history := api.Group("/history", middleware.AuthRequired())
+		{
+			history.GET("/pokerstars", h.ExportPokerStars)
+			history.GET("/ohh", h.ExportOHH)
+		}
+		
 		// 管理员路由
 		admin := api.Group("/admin")
 		{
@@ -97,6 +112,9 @@ func setupRoutes(r *gin.Engine, h *handlers.Handler) {
 				adminAPI.PUT("/users/:id", h.UpdateUser)
 				adminAPI.GET("/rooms", h.GetRoomsAdmin)
 				adminAPI.GET("/stats", h.GetStats)
+				adminAPI.POST("/games/import/ohh", h.ImportOHH)
+				adminAPI.GET("/games/unsaved", h.GetUnsavedGames)
+				adminAPI.POST("/games/unsaved/:game_id/retry", h.RetryUnsavedGame)
 			}
 		}
 	}
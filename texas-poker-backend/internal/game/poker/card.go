@@ -98,66 +98,16 @@ func (c Card) String() string {
 	return fmt.Sprintf("%s%s", c.Rank.String(), c.Suit.String())
 }
 
+// IsValid 检查点数和花色是否在标准52张牌的范围内
+func (c Card) IsValid() bool {
+	return c.Rank >= Two && c.Rank <= Ace && c.Suit >= Spades && c.Suit <= Clubs
+}
+
 // NewCard 创建新扑克牌
 func NewCard(rank Rank, suit Suit) Card {
 	return Card{Rank: rank, Suit: suit}
 }
 
-// ParseCard 从字符串解析扑克牌（如 "AS" = Ace of Spades）
-func ParseCard(s string) (Card, error) {
-	if len(s) != 2 {
-		return Card{}, fmt.Errorf("invalid card string: %s", s)
-	}
-
-	var rank Rank
-	switch s[0] {
-	case '2':
-		rank = Two
-	case '3':
-		rank = Three
-	case '4':
-		rank = Four
-	case '5':
-		rank = Five
-	case '6':
-		rank = Six
-	case '7':
-		rank = Seven
-	case '8':
-		rank = Eight
-	case '9':
-		rank = Nine
-	case 'T':
-		rank = Ten
-	case 'J':
-		rank = Jack
-	case 'Q':
-		rank = Queen
-	case 'K':
-		rank = King
-	case 'A':
-		rank = Ace
-	default:
-		return Card{}, fmt.Errorf("invalid rank: %c", s[0])
-	}
-
-	var suit Suit
-	switch s[1] {
-	case 'S':
-		suit = Spades
-	case 'H':
-		suit = Hearts
-	case 'D':
-		suit = Diamonds
-	case 'C':
-		suit = Clubs
-	default:
-		return Card{}, fmt.Errorf("invalid suit: %c", s[1])
-	}
-
-	return NewCard(rank, suit), nil
-}
-
 // HandType 牌型枚举（按强度排序）
 type HandType int
 
@@ -204,18 +154,15 @@ func (ht HandType) String() string {
 
 // Hand 手牌结构
 type Hand struct {
-	Cards []Card   `json:"cards"`
-	Type  HandType `json:"type"`
-	Ranks []Rank   `json:"ranks"` // 用于比较的关键点数（按重要性排序）
+	Cards    []Card       `json:"cards"`
+	Type     HandType     `json:"type"`
+	Ranks    []Rank       `json:"ranks"`    // 用于比较的关键点数（按重要性排序）
+	Strength HandStrength `json:"strength"` // 手牌强度，可直接比较大小
 }
 
-// String 手牌转字符串
+// String 手牌转字符串（中文描述和五张牌，如 "两对，K和7 [KsKd7h7cAs]"）
 func (h Hand) String() string {
-	cardStrs := make([]string, len(h.Cards))
-	for i, card := range h.Cards {
-		cardStrs[i] = card.String()
-	}
-	return fmt.Sprintf("%s: %v", h.Type.String(), cardStrs)
+	return fmt.Sprintf("%s [%s]", h.Describe(Chinese), Cards(h.Cards))
 }
 
 // Cards 卡牌集合类型
@@ -1,46 +1,108 @@
 // 牌堆管理系统
-// 作用：创建、洗牌、发牌，管理德州扑克的52张标准牌
+// 作用：创建、洗牌、发牌，管理德州扑克的52张标准牌和短牌的36张牌
 
 package poker
 
-import (
-	"math/rand"
-	"time"
-)
-
 // Deck 牌堆结构
 type Deck struct {
 	cards []Card
 	index int // 当前发牌位置
+	rng   RNG // 洗牌使用的随机数源
 }
 
-// NewDeck 创建新的标准52张牌堆
-func NewDeck() *Deck {
-	cards := make([]Card, 0, 52)
+// DeckType 牌堆类型
+type DeckType int
+
+const (
+	StandardDeck DeckType = iota // 标准52张牌
+	ShortDeck                    // 短牌36张（去掉每种花色的2到5）
+)
+
+// Ranks 牌堆中每种花色包含的点数（从小到大）
+func (t DeckType) Ranks() []Rank {
+	if t == ShortDeck {
+		return []Rank{Six, Seven, Eight, Nine, Ten, Jack, Queen, King, Ace}
+	}
+	return []Rank{Two, Three, Four, Five, Six, Seven, Eight, Nine, Ten, Jack, Queen, King, Ace}
+}
+
+// Size 牌堆的张数
+func (t DeckType) Size() int {
+	return 4 * len(t.Ranks())
+}
+
+// Contains 判断牌是否属于该类型的牌堆
+func (t DeckType) Contains(card Card) bool {
+	if !card.IsValid() {
+		return false
+	}
+	return t != ShortDeck || card.Rank >= Six
+}
+
+// Cards 按标准顺序（黑桃、红桃、方块、梅花，每种花色点数从小到大）列出牌堆中的所有牌
+func (t DeckType) Cards() []Card {
+	ranks := t.Ranks()
+	cards := make([]Card, 0, 4*len(ranks))
 	
-	// 创建52张标准扑克牌
 	suits := []Suit{Spades, Hearts, Diamonds, Clubs}
-	ranks := []Rank{Two, Three, Four, Five, Six, Seven, Eight, Nine, Ten, Jack, Queen, King, Ace}
-	
 	for _, suit := range suits {
 		for _, rank := range ranks {
 			cards = append(cards, NewCard(rank, suit))
 		}
 	}
+	return cards
+}
+
+// String 牌堆类型转字符串
+func (t DeckType) String() string {
+	if t == ShortDeck {
+		return "短牌"
+	}
+	return "标准牌"
+}
+
+// NewDeck 创建新的标准52张牌堆（使用crypto/rand洗牌）
+func NewDeck() *Deck {
+	return NewDeckWithRNG(NewCryptoRNG())
+}
+
+// NewShortDeck 创建新的36张短牌牌堆（使用crypto/rand洗牌）
+func NewShortDeck() *Deck {
+	return NewDeckOfType(ShortDeck, NewCryptoRNG())
+}
+
+// NewDeckWithRNG 创建使用指定随机数源洗牌的标准52张牌堆（rng为nil时使用crypto/rand）
+func NewDeckWithRNG(rng RNG) *Deck {
+	return NewDeckOfType(StandardDeck, rng)
+}
+
+// NewDeckOfType 创建指定类型、使用指定随机数源洗牌的牌堆（rng为nil时使用crypto/rand）
+func NewDeckOfType(deckType DeckType, rng RNG) *Deck {
+	if rng == nil {
+		rng = NewCryptoRNG()
+	}
 	
 	return &Deck{
-		cards: cards,
+		cards: deckType.Cards(),
+		index: 0,
+		rng:   rng,
+	}
+}
+
+// NewStackedDeck 创建按给定顺序发牌的牌堆（不洗牌，用于按已知的牌重放牌局）
+func NewStackedDeck(cards []Card) *Deck {
+	return &Deck{
+		cards: append([]Card(nil), cards...),
 		index: 0,
+		rng:   NewCryptoRNG(),
 	}
 }
 
 // Shuffle 洗牌
 func (d *Deck) Shuffle() {
-	rand.Seed(time.Now().UnixNano())
-	
 	// Fisher-Yates洗牌算法
 	for i := len(d.cards) - 1; i > 0; i-- {
-		j := rand.Intn(i + 1)
+		j := d.rng.Intn(i + 1)
 		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
 	}
 	
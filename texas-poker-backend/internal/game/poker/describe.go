@@ -0,0 +1,178 @@
+// 手牌描述
+// 作用：生成中英文的完整牌型描述（如 "两对，K和7" / "Two Pair, Kings and Sevens"），并标出组成牌型的五张牌和其中用到的底牌
+
+package poker
+
+import (
+	"fmt"
+)
+
+// Language 描述使用的语言
+type Language string
+
+const (
+	Chinese Language = "zh"
+	English Language = "en"
+)
+
+// HandDescription 手牌的结构化描述
+type HandDescription struct {
+	Type      HandType `json:"type"`
+	Name      string   `json:"name"`       // 牌型中文名称（如 "两对"）
+	NameEn    string   `json:"name_en"`    // 牌型英文名称（如 "Two Pair"）
+	Text      string   `json:"text"`       // 中文完整描述（如 "两对，K和7"）
+	TextEn    string   `json:"text_en"`    // 英文完整描述（如 "Two Pair, Kings and Sevens"）
+	Cards     []Card   `json:"cards"`      // 组成牌型的五张牌（按重要性排列）
+	HoleCards []Card   `json:"hole_cards"` // 五张牌中用到的底牌（全部使用公共牌时为空）
+}
+
+// rankNamesEn 点数的英文单数和复数名称
+var rankNamesEn = map[Rank][2]string{
+	Two:   {"Two", "Twos"},
+	Three: {"Three", "Threes"},
+	Four:  {"Four", "Fours"},
+	Five:  {"Five", "Fives"},
+	Six:   {"Six", "Sixes"},
+	Seven: {"Seven", "Sevens"},
+	Eight: {"Eight", "Eights"},
+	Nine:  {"Nine", "Nines"},
+	Ten:   {"Ten", "Tens"},
+	Jack:  {"Jack", "Jacks"},
+	Queen: {"Queen", "Queens"},
+	King:  {"King", "Kings"},
+	Ace:   {"Ace", "Aces"},
+}
+
+// EnglishName 点数的英文名称（plural为true时为复数）
+func (r Rank) EnglishName(plural bool) string {
+	names, ok := rankNamesEn[r]
+	if !ok {
+		return "?"
+	}
+	if plural {
+		return names[1]
+	}
+	return names[0]
+}
+
+// chineseName 点数在中文描述中的写法（10写作"10"，其余与记法相同）
+func (r Rank) chineseName() string {
+	if r == Ten {
+		return "10"
+	}
+	return r.String()
+}
+
+// EnglishName 牌型的英文名称
+func (ht HandType) EnglishName() string {
+	switch ht {
+	case HighCard:
+		return "High Card"
+	case OnePair:
+		return "One Pair"
+	case TwoPair:
+		return "Two Pair"
+	case ThreeOfAKind:
+		return "Three of a Kind"
+	case Straight:
+		return "Straight"
+	case Flush:
+		return "Flush"
+	case FullHouse:
+		return "Full House"
+	case FourOfAKind:
+		return "Four of a Kind"
+	case StraightFlush:
+		return "Straight Flush"
+	case RoyalFlush:
+		return "Royal Flush"
+	default:
+		return "Unknown"
+	}
+}
+
+// Name 指定语言的牌型名称
+func (ht HandType) Name(lang Language) string {
+	if lang == English {
+		return ht.EnglishName()
+	}
+	return ht.String()
+}
+
+// Describe 指定语言的完整牌型描述（关键点数缺失时只返回牌型名称）
+func (h Hand) Describe(lang Language) string {
+	ranks := h.Ranks
+	if len(ranks) == 0 && h.Strength != 0 {
+		ranks = h.Strength.Ranks()
+	}
+	if h.Type == RoyalFlush || len(ranks) < requiredRanks(h.Type) {
+		return h.Type.Name(lang)
+	}
+
+	if lang == English {
+		return h.Type.EnglishName() + ", " + describeRanksEn(h.Type, ranks)
+	}
+	return h.Type.String() + "，" + describeRanksZh(h.Type, ranks)
+}
+
+// requiredRanks 完整描述需要的关键点数个数
+func requiredRanks(handType HandType) int {
+	switch handType {
+	case TwoPair, FullHouse:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// describeRanksEn 英文描述中牌型名称之后的部分
+func describeRanksEn(handType HandType, ranks []Rank) string {
+	switch handType {
+	case OnePair, ThreeOfAKind, FourOfAKind:
+		return ranks[0].EnglishName(true)
+	case TwoPair:
+		return ranks[0].EnglishName(true) + " and " + ranks[1].EnglishName(true)
+	case FullHouse:
+		return ranks[0].EnglishName(true) + " full of " + ranks[1].EnglishName(true)
+	case HighCard:
+		return ranks[0].EnglishName(false)
+	default:
+		return ranks[0].EnglishName(false) + " High"
+	}
+}
+
+// describeRanksZh 中文描述中牌型名称之后的部分
+func describeRanksZh(handType HandType, ranks []Rank) string {
+	switch handType {
+	case OnePair, ThreeOfAKind, FourOfAKind, HighCard:
+		return ranks[0].chineseName()
+	case TwoPair:
+		return ranks[0].chineseName() + "和" + ranks[1].chineseName()
+	case FullHouse:
+		return fmt.Sprintf("三条%s带一对%s", ranks[0].chineseName(), ranks[1].chineseName())
+	default:
+		return ranks[0].chineseName() + "高"
+	}
+}
+
+// DescribeHand 生成手牌的中英文描述，hole为玩家的底牌，用于标出五张牌中用到了哪几张
+func DescribeHand(hand Hand, hole []Card) HandDescription {
+	description := HandDescription{
+		Type:      hand.Type,
+		Name:      hand.Type.String(),
+		NameEn:    hand.Type.EnglishName(),
+		Text:      hand.Describe(Chinese),
+		TextEn:    hand.Describe(English),
+		Cards:     hand.Cards,
+		HoleCards: make([]Card, 0, len(hole)),
+	}
+	for _, card := range hand.Cards {
+		for _, holeCard := range hole {
+			if card == holeCard {
+				description.HoleCards = append(description.HoleCards, card)
+				break
+			}
+		}
+	}
+	return description
+}
@@ -0,0 +1,87 @@
+// 手牌描述测试
+// 作用：校验各牌型的中英文描述，以及五张牌中用到的底牌
+
+package poker
+
+import (
+	"testing"
+)
+
+func TestDescribeHand(t *testing.T) {
+	tests := []struct {
+		name     string
+		gameType GameType
+		hole     string
+		board    string
+		wantText string
+		wantEn   string
+		wantHole string // 五张牌中用到的底牌（紧凑记法，按五张牌的重要性排列）
+	}{
+		{name: "高牌", hole: "AsJd", board: "9c7h5s3d2c", wantText: "高牌，A", wantEn: "High Card, Ace", wantHole: "AsJd"},
+		{name: "一对", hole: "AsQd", board: "Ac7h5s3d2c", wantText: "一对，A", wantEn: "One Pair, Aces", wantHole: "AsQd"},
+		{name: "两对", hole: "KsKd", board: "7c7h2s9dQc", wantText: "两对，K和7", wantEn: "Two Pair, Kings and Sevens", wantHole: "KsKd"},
+		{name: "三条10", hole: "TcTd", board: "Th7h2s9dQc", wantText: "三条，10", wantEn: "Three of a Kind, Tens", wantHole: "TcTd"},
+		{name: "顺子", hole: "9s8d", board: "7c6h5s2d2c", wantText: "顺子，9高", wantEn: "Straight, Nine High", wantHole: "9s8d"},
+		{name: "A当作1的顺子", hole: "As2d", board: "3c4h5sKdKc", wantText: "顺子，5高", wantEn: "Straight, Five High", wantHole: "2dAs"},
+		{name: "同花只用到一张底牌", hole: "Ah2c", board: "Jh9h7h6h3d", wantText: "同花，A高", wantEn: "Flush, Ace High", wantHole: "Ah"},
+		{name: "葫芦", hole: "AsAd", board: "AcKdKh2c3s", wantText: "葫芦，三条A带一对K", wantEn: "Full House, Aces full of Kings", wantHole: "AsAd"},
+		{name: "四条", hole: "9s9d", board: "9c9hKd2c3s", wantText: "四条，9", wantEn: "Four of a Kind, Nines", wantHole: "9s9d"},
+		{name: "同花顺", hole: "9h8h", board: "7h6h5h2d2c", wantText: "同花顺，9高", wantEn: "Straight Flush, Nine High", wantHole: "9h8h"},
+		{name: "皇家同花顺", hole: "AsKs", board: "QsJsTs2d3c", wantText: "皇家同花顺", wantEn: "Royal Flush", wantHole: "AsKs"},
+		{name: "全部使用公共牌", hole: "2c3d", board: "AsKsQsJsTs", wantText: "皇家同花顺", wantEn: "Royal Flush", wantHole: ""},
+		{name: "短牌中同花大于葫芦", gameType: ShortDeckHoldem, hole: "AhAd", board: "AcJh9h7h6h", wantText: "同花，A高", wantEn: "Flush, Ace High", wantHole: "Ah"},
+		{name: "奥马哈恰好用两张底牌", gameType: PotLimitOmaha, hole: "KsKd7s2c", board: "KcQh7h7d2s", wantText: "葫芦，三条K带一对7", wantEn: "Full House, Kings full of Sevens", wantHole: "KsKd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameType := tt.gameType
+			if gameType == "" {
+				gameType = TexasHoldem
+			}
+			hole, board := parseTestCards(t, tt.hole), parseTestCards(t, tt.board)
+			hand, err := gameType.Evaluate(hole, board)
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+
+			description := DescribeHand(hand, hole)
+			if description.Text != tt.wantText {
+				t.Errorf("Text = %q，期望 %q", description.Text, tt.wantText)
+			}
+			if description.TextEn != tt.wantEn {
+				t.Errorf("TextEn = %q，期望 %q", description.TextEn, tt.wantEn)
+			}
+			if got := Cards(description.HoleCards).String(); got != tt.wantHole {
+				t.Errorf("HoleCards = %s，期望 %s", got, tt.wantHole)
+			}
+			if len(description.Cards) != 5 {
+				t.Errorf("Cards = %v，期望五张牌", description.Cards)
+			}
+			if description.Name != hand.Type.String() || description.NameEn != hand.Type.EnglishName() {
+				t.Errorf("Name = %q / %q，与牌型 %s 不一致", description.Name, description.NameEn, hand.Type)
+			}
+		})
+	}
+}
+
+func TestDescribeWithoutRanks(t *testing.T) {
+	tests := []struct {
+		hand   Hand
+		wantZh string
+		wantEn string
+	}{
+		{hand: Hand{Type: TwoPair, Ranks: []Rank{King}}, wantZh: "两对", wantEn: "Two Pair"},
+		{hand: Hand{Type: OnePair}, wantZh: "一对", wantEn: "One Pair"},
+		{hand: Hand{Type: OnePair, Strength: NewHandStrength(OnePair, []Rank{Queen, Ace, Nine, Two})}, wantZh: "一对，Q", wantEn: "One Pair, Queens"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.hand.Describe(Chinese); got != tt.wantZh {
+			t.Errorf("Describe(Chinese) = %q，期望 %q", got, tt.wantZh)
+		}
+		if got := tt.hand.Describe(English); got != tt.wantEn {
+			t.Errorf("Describe(English) = %q，期望 %q", got, tt.wantEn)
+		}
+	}
+}
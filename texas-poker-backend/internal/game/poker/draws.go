@@ -0,0 +1,385 @@
+// 听牌分析
+// 作用：根据两张底牌和翻牌/转牌（3~4张公共牌）识别同花听牌、两头顺子听牌、卡顺、后门听牌和高张，
+// 并列出能让牌型升级的具体补牌（outs）及命中概率
+
+package poker
+
+import (
+	"fmt"
+)
+
+// DrawType 听牌类型
+type DrawType string
+
+const (
+	FlushDraw            DrawType = "flush_draw"        // 同花听牌（四张同花）
+	OpenEndedDraw        DrawType = "open_ended"        // 两头顺子听牌
+	DoubleGutshotDraw    DrawType = "double_gutshot"    // 双卡顺
+	GutshotDraw          DrawType = "gutshot"           // 卡顺
+	BackdoorFlushDraw    DrawType = "backdoor_flush"    // 后门同花听牌（翻牌圈三张同花）
+	BackdoorStraightDraw DrawType = "backdoor_straight" // 后门顺子听牌（翻牌圈还差两张成顺）
+	OvercardsDraw        DrawType = "overcards"         // 底牌高于所有公共牌
+)
+
+// String 听牌类型的中文名称
+func (t DrawType) String() string {
+	switch t {
+	case FlushDraw:
+		return "同花听牌"
+	case OpenEndedDraw:
+		return "两头顺子听牌"
+	case DoubleGutshotDraw:
+		return "双卡顺"
+	case GutshotDraw:
+		return "卡顺"
+	case BackdoorFlushDraw:
+		return "后门同花听牌"
+	case BackdoorStraightDraw:
+		return "后门顺子听牌"
+	case OvercardsDraw:
+		return "高张"
+	default:
+		return "未知听牌"
+	}
+}
+
+// Draw 一种听牌及其补牌
+type Draw struct {
+	Type DrawType `json:"type"`
+	Name string   `json:"name"`
+	Outs []Card   `json:"outs"` // 完成该听牌的牌（后门听牌需要连续两张，为空）
+}
+
+// Out 一张能让牌型升级的补牌
+type Out struct {
+	Card       Card     `json:"card"`
+	ImprovesTo HandType `json:"improves_to"`
+	Name       string   `json:"improves_to_name"` // 升级后的牌型名称
+}
+
+// DrawAnalysis 听牌分析结果
+type DrawAnalysis struct {
+	Hand     HandType `json:"hand"`      // 当前牌型
+	HandName string   `json:"hand_name"` // 当前牌型名称
+	Draws    []Draw   `json:"draws"`
+	Outs     []Out    `json:"outs"`      // 所有让牌型升级（且不只是公共牌自身升级）的补牌
+	NextCard float64  `json:"next_card"` // 下一张牌命中补牌的概率
+	ByRiver  float64  `json:"by_river"`  // 到河牌为止至少命中一张补牌的概率
+}
+
+// AnalyzeDraws 按德州扑克规则分析听牌
+func AnalyzeDraws(hole, board []Card) (*DrawAnalysis, error) {
+	return TexasHoldem.AnalyzeDraws(hole, board)
+}
+
+// AnalyzeDraws 按玩法规则分析两张底牌在翻牌或转牌圈的听牌（奥马哈玩法暂不支持）
+// Draws只列出完成后能让牌型升级的听牌：如已经成同花时，顺子不大于当前牌型，即使差一张成顺也不报告顺子听牌；
+// 同花顺等仍能升级的补牌由Outs逐张列出，不受此限制
+func (g GameType) AnalyzeDraws(hole, board []Card) (*DrawAnalysis, error) {
+	if g.omaha() {
+		return nil, fmt.Errorf("%s暂不支持听牌分析", g)
+	}
+	if len(hole) != 2 {
+		return nil, fmt.Errorf("底牌必须是2张，实际为%d张", len(hole))
+	}
+	if len(board) < 3 || len(board) > 4 {
+		return nil, fmt.Errorf("听牌分析需要3或4张公共牌，实际为%d张", len(board))
+	}
+	known := append(append(make([]Card, 0, 6), hole...), board...)
+	if err := validateCards(known); err != nil {
+		return nil, err
+	}
+	deckType := g.DeckType()
+	for _, card := range known {
+		if !deckType.Contains(card) {
+			return nil, fmt.Errorf("%s中没有%s", deckType, card)
+		}
+	}
+
+	current := g.Strength(hole, board)
+	analysis := &DrawAnalysis{
+		Hand:     current.Type(),
+		HandName: current.Type().String(),
+		Draws:    []Draw{},
+		Outs:     []Out{},
+	}
+
+	unseen := make([]Card, 0, deckType.Size())
+	for _, card := range deckType.Cards() {
+		if !containsCard(known, card) {
+			unseen = append(unseen, card)
+		}
+	}
+
+	// 补牌：加入后牌型升级，且升级后的牌型高于公共牌自身组成的牌型（排除所有人共享的升级）
+	next := append(make([]Card, 0, 5), board...)
+	for _, card := range unseen {
+		next = append(next[:len(board)], card)
+		improved := g.Strength(hole, next).Type()
+		if g.beats(improved, current.Type()) && g.beats(improved, g.boardType(next)) {
+			analysis.Outs = append(analysis.Outs, Out{Card: card, ImprovesTo: improved, Name: improved.String()})
+		}
+	}
+
+	// 已有同花或顺子听牌时不再报告同类的后门听牌
+	flush, hasFlushDraw := g.flushDraw(hole, board, unseen, current)
+	if hasFlushDraw {
+		analysis.Draws = append(analysis.Draws, flush)
+	}
+	straight, hasStraightDraw := g.straightDraw(hole, board, unseen, current)
+	if hasStraightDraw {
+		analysis.Draws = append(analysis.Draws, straight)
+	}
+	if len(board) == 3 {
+		if draw, ok := g.backdoorFlushDraw(hole, board, current); ok && !hasFlushDraw {
+			analysis.Draws = append(analysis.Draws, draw)
+		}
+		if draw, ok := g.backdoorStraightDraw(hole, board, current); ok && !hasStraightDraw {
+			analysis.Draws = append(analysis.Draws, draw)
+		}
+	}
+	if draw, ok := g.overcards(hole, board, unseen, current); ok {
+		analysis.Draws = append(analysis.Draws, draw)
+	}
+
+	// 命中概率：翻牌圈还有两张牌，转牌圈只剩河牌
+	outs, remaining := float64(len(analysis.Outs)), float64(len(unseen))
+	analysis.NextCard = outs / remaining
+	analysis.ByRiver = analysis.NextCard
+	if len(board) == 3 {
+		analysis.ByRiver = 1 - (remaining-outs)*(remaining-outs-1)/(remaining*(remaining-1))
+	}
+	return analysis, nil
+}
+
+// boardType 公共牌自身组成的牌型（不足5张时只考虑对子、三条和四条）
+func (g GameType) boardType(board []Card) HandType {
+	if len(board) >= MinHandCards {
+		if g == ShortDeckHoldem {
+			return ShortDeckStrength(board).Type()
+		}
+		return Evaluate(board).Type()
+	}
+
+	var counts [Ace + 1]int
+	pairs, trips := 0, 0
+	handType := HighCard
+	for _, card := range board {
+		counts[card.Rank]++
+		switch counts[card.Rank] {
+		case 2:
+			pairs++
+		case 3:
+			trips++
+		case 4:
+			return FourOfAKind
+		}
+	}
+	switch {
+	case trips > 0:
+		handType = ThreeOfAKind
+	case pairs >= 2:
+		handType = TwoPair
+	case pairs == 1:
+		handType = OnePair
+	}
+	return handType
+}
+
+// flushDraw 同花听牌：某种花色已有四张（至少一张是底牌），还没有成同花
+func (g GameType) flushDraw(hole, board, unseen []Card, current HandStrength) (Draw, bool) {
+	if !g.beats(Flush, current.Type()) {
+		return Draw{}, false
+	}
+	for suit := Spades; suit <= Clubs; suit++ {
+		if countSuit(hole, suit) > 0 && countSuit(hole, suit)+countSuit(board, suit) == 4 {
+			return Draw{Type: FlushDraw, Name: FlushDraw.String(), Outs: suitCards(unseen, suit)}, true
+		}
+	}
+	return Draw{}, false
+}
+
+// backdoorFlushDraw 后门同花听牌：翻牌圈某种花色有三张（至少一张是底牌）
+func (g GameType) backdoorFlushDraw(hole, board []Card, current HandStrength) (Draw, bool) {
+	if !g.beats(Flush, current.Type()) {
+		return Draw{}, false
+	}
+	for suit := Spades; suit <= Clubs; suit++ {
+		if countSuit(hole, suit) > 0 && countSuit(hole, suit)+countSuit(board, suit) == 3 {
+			return Draw{Type: BackdoorFlushDraw, Name: BackdoorFlushDraw.String(), Outs: []Card{}}, true
+		}
+	}
+	return Draw{}, false
+}
+
+// straightDraw 顺子听牌：统计再来一张就能组成更大顺子（且不是公共牌自身成顺）的点数；当前牌型不小于顺子时不算听牌
+// 一个点数为卡顺；两个以上且已有四张连续点数为两头顺子听牌，否则为双卡顺
+func (g GameType) straightDraw(hole, board, unseen []Card, current HandStrength) (Draw, bool) {
+	if !g.beats(Straight, current.Type()) {
+		return Draw{}, false
+	}
+
+	mask, boardMask := rankMask(hole)|rankMask(board), rankMask(board)
+	var completing []Rank
+	for _, rank := range g.DeckType().Ranks() {
+		bit := rankBit(rank)
+		if high := g.straightHigh(mask | bit); high > 0 && high > g.straightHigh(boardMask|bit) {
+			completing = append(completing, rank)
+		}
+	}
+
+	var drawType DrawType
+	switch {
+	case len(completing) == 0:
+		return Draw{}, false
+	case len(completing) == 1:
+		drawType = GutshotDraw
+	case g.hasFourInRow(mask):
+		drawType = OpenEndedDraw
+	default:
+		drawType = DoubleGutshotDraw
+	}
+
+	outs := []Card{}
+	for _, card := range unseen {
+		for _, rank := range completing {
+			if card.Rank == rank {
+				outs = append(outs, card)
+			}
+		}
+	}
+	return Draw{Type: drawType, Name: drawType.String(), Outs: outs}, true
+}
+
+// backdoorStraightDraw 后门顺子听牌：翻牌圈再来两张合适的牌就能组成用到底牌的顺子
+func (g GameType) backdoorStraightDraw(hole, board []Card, current HandStrength) (Draw, bool) {
+	if !g.beats(Straight, current.Type()) {
+		return Draw{}, false
+	}
+
+	mask, boardMask := rankMask(hole)|rankMask(board), rankMask(board)
+	ranks := g.DeckType().Ranks()
+	for i, first := range ranks {
+		for _, second := range ranks[i+1:] {
+			bits := rankBit(first) | rankBit(second)
+			if mask&bits != 0 {
+				continue
+			}
+			if high := g.straightHigh(mask | bits); high > 0 && high > g.straightHigh(boardMask|bits) {
+				return Draw{Type: BackdoorStraightDraw, Name: BackdoorStraightDraw.String(), Outs: []Card{}}, true
+			}
+		}
+	}
+	return Draw{}, false
+}
+
+// overcards 高张：底牌不是对子、没有与公共牌配对，且有底牌大于所有公共牌，补牌为与高张配对后让牌型升级的牌
+// 按补牌实际组成的牌型判断升级：已经成同花时配对高张不算升级；公共牌是三条时配对高张组成葫芦，仍是补牌
+func (g GameType) overcards(hole, board, unseen []Card, current HandStrength) (Draw, bool) {
+	if hole[0].Rank == hole[1].Rank {
+		return Draw{}, false
+	}
+
+	var boardHigh Rank
+	for _, card := range board {
+		if card.Rank == hole[0].Rank || card.Rank == hole[1].Rank {
+			return Draw{}, false
+		}
+		boardHigh = max(boardHigh, card.Rank)
+	}
+
+	outs := []Card{}
+	next := append(make([]Card, 0, 5), board...)
+	for _, card := range unseen {
+		if card.Rank <= boardHigh || (card.Rank != hole[0].Rank && card.Rank != hole[1].Rank) {
+			continue
+		}
+		next = append(next[:len(board)], card)
+		if g.beats(g.Strength(hole, next).Type(), current.Type()) {
+			outs = append(outs, card)
+		}
+	}
+	if len(outs) == 0 {
+		return Draw{}, false
+	}
+	return Draw{Type: OvercardsDraw, Name: OvercardsDraw.String(), Outs: outs}, true
+}
+
+// beats 按玩法的牌型排列判断牌型a是否大于牌型b
+func (g GameType) beats(a, b HandType) bool {
+	rankings := g.HandRankings()
+	position := func(handType HandType) int {
+		for i, t := range rankings {
+			if t == handType {
+				return i
+			}
+		}
+		return len(rankings)
+	}
+	return position(a) < position(b)
+}
+
+// straightHigh 点数掩码中最大顺子的最高牌（短牌包括A-6-7-8-9），没有顺子时为0
+func (g GameType) straightHigh(mask uint16) Rank {
+	high := straightTable[mask]
+	if high == 0 && g == ShortDeckHoldem && mask&shortWheel == shortWheel {
+		return Nine
+	}
+	return high
+}
+
+// hasFourInRow 点数掩码中是否有四张连续的点数（两端都还能接牌）
+func (g GameType) hasFourInRow(mask uint16) bool {
+	low := Two
+	if g == ShortDeckHoldem {
+		low = Six
+	}
+	for start := low; start+4 <= Ace; start++ {
+		run := rankBit(start) | rankBit(start+1) | rankBit(start+2) | rankBit(start+3)
+		if mask&run == run {
+			return true
+		}
+	}
+	return false
+}
+
+// rankMask 牌的点数掩码
+func rankMask(cards []Card) uint16 {
+	var mask uint16
+	for _, card := range cards {
+		mask |= rankBit(card.Rank)
+	}
+	return mask
+}
+
+// countSuit 统计某种花色的张数
+func countSuit(cards []Card, suit Suit) int {
+	count := 0
+	for _, card := range cards {
+		if card.Suit == suit {
+			count++
+		}
+	}
+	return count
+}
+
+// suitCards 某种花色的牌
+func suitCards(cards []Card, suit Suit) []Card {
+	result := []Card{}
+	for _, card := range cards {
+		if card.Suit == suit {
+			result = append(result, card)
+		}
+	}
+	return result
+}
+
+// containsCard 判断牌是否在列表中
+func containsCard(cards []Card, card Card) bool {
+	for _, c := range cards {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
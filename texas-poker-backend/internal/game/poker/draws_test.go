@@ -0,0 +1,197 @@
+// 听牌分析测试
+// 作用：用已知的补牌数校验同花听牌、两头顺子听牌、卡顺、后门听牌和高张的识别，以及命中概率
+
+package poker
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeDraws(t *testing.T) {
+	tests := []struct {
+		name      string
+		gameType  GameType
+		hole      string
+		board     string
+		wantHand  HandType
+		wantDraws []DrawType
+		wantOuts  []int // 每种听牌的补牌数
+		wantTotal int   // 所有让牌型升级的补牌数
+		wantNext  float64
+		wantRiver float64
+	}{
+		{
+			name:      "同花听牌加两张高张",
+			hole:      "AhKh",
+			board:     "7h2h9c",
+			wantHand:  HighCard,
+			wantDraws: []DrawType{FlushDraw, OvercardsDraw},
+			wantOuts:  []int{9, 6},
+			wantTotal: 15,
+			wantNext:  15.0 / 47,
+			wantRiver: 1 - 32.0*31/(47*46),
+		},
+		{
+			name:      "两头顺子听牌",
+			hole:      "9s8d",
+			board:     "7c6h2s",
+			wantHand:  HighCard,
+			wantDraws: []DrawType{OpenEndedDraw, OvercardsDraw},
+			wantOuts:  []int{8, 6},
+			wantTotal: 14,
+			wantNext:  14.0 / 47,
+			wantRiver: 1 - 33.0*32/(47*46),
+		},
+		{
+			name:      "卡顺",
+			hole:      "AsKd",
+			board:     "QcJh3s",
+			wantHand:  HighCard,
+			wantDraws: []DrawType{GutshotDraw, OvercardsDraw},
+			wantOuts:  []int{4, 6},
+			wantTotal: 10,
+			wantNext:  10.0 / 47,
+			wantRiver: 1 - 37.0*36/(47*46),
+		},
+		{
+			name:      "双卡顺",
+			hole:      "9c7d",
+			board:     "Js8h5c",
+			wantHand:  HighCard,
+			wantDraws: []DrawType{DoubleGutshotDraw},
+			wantOuts:  []int{8},
+			wantTotal: 14, // 8张成顺，6张与底牌配对
+			wantNext:  14.0 / 47,
+			wantRiver: 1 - 33.0*32/(47*46),
+		},
+		{
+			name:      "后门同花和后门顺子听牌",
+			hole:      "Ts9s",
+			board:     "8s3d2c",
+			wantHand:  HighCard,
+			wantDraws: []DrawType{BackdoorFlushDraw, BackdoorStraightDraw, OvercardsDraw},
+			wantOuts:  []int{0, 0, 6},
+			wantTotal: 6,
+			wantNext:  6.0 / 47,
+			wantRiver: 1 - 41.0*40/(47*46),
+		},
+		{
+			name:      "转牌圈只剩河牌",
+			hole:      "AhKh",
+			board:     "7h2h9cTd",
+			wantHand:  HighCard,
+			wantDraws: []DrawType{FlushDraw, OvercardsDraw},
+			wantOuts:  []int{9, 6},
+			wantTotal: 15,
+			wantNext:  15.0 / 46,
+			wantRiver: 15.0 / 46,
+		},
+		{
+			// 差一张3或8就成顺子，但顺子小于同花，不报告顺子听牌；3h、8h成同花顺仍是补牌
+			name:      "已经成同花时不报告顺子听牌",
+			hole:      "6h5h",
+			board:     "Kh7h4h",
+			wantHand:  Flush,
+			wantDraws: []DrawType{},
+			wantOuts:  []int{},
+			wantTotal: 2,
+			wantNext:  2.0 / 47,
+			wantRiver: 1 - 45.0*44/(47*46),
+		},
+		{
+			// 配对A或K只组成一对，小于已有的同花，不报告高张
+			name:      "已经成同花时配对高张不算升级",
+			hole:      "AhKh",
+			board:     "Qh7h2h",
+			wantHand:  Flush,
+			wantDraws: []DrawType{},
+			wantOuts:  []int{},
+			wantTotal: 0,
+			wantNext:  0,
+			wantRiver: 0,
+		},
+		{
+			// 公共牌是三条时配对A或K组成葫芦；7s让公共牌自身成为四条，不计入补牌
+			name:      "公共牌三条时配对高张组成葫芦",
+			hole:      "AsKd",
+			board:     "7c7h7d",
+			wantHand:  ThreeOfAKind,
+			wantDraws: []DrawType{OvercardsDraw},
+			wantOuts:  []int{6},
+			wantTotal: 6,
+			wantNext:  6.0 / 47,
+			wantRiver: 1 - 41.0*40/(47*46),
+		},
+		{
+			// 2让公共牌自身成为两对，所有人共享这一升级，不计入补牌
+			name:      "公共牌自身升级的牌不算补牌",
+			hole:      "AsKd",
+			board:     "7c7h2d",
+			wantHand:  OnePair,
+			wantDraws: []DrawType{OvercardsDraw},
+			wantOuts:  []int{6},
+			wantTotal: 6,
+			wantNext:  6.0 / 47,
+			wantRiver: 1 - 41.0*40/(47*46),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameType := tt.gameType
+			if gameType == "" {
+				gameType = TexasHoldem
+			}
+			hole, board := parseTestCards(t, tt.hole), parseTestCards(t, tt.board)
+
+			analysis, err := gameType.AnalyzeDraws(hole, board)
+			if err != nil {
+				t.Fatalf("AnalyzeDraws: %v", err)
+			}
+			if analysis.Hand != tt.wantHand {
+				t.Errorf("Hand = %s，期望 %s", analysis.Hand, tt.wantHand)
+			}
+
+			draws, outs := []DrawType{}, []int{}
+			for _, draw := range analysis.Draws {
+				draws = append(draws, draw.Type)
+				outs = append(outs, len(draw.Outs))
+			}
+			if !reflect.DeepEqual(draws, tt.wantDraws) || !reflect.DeepEqual(outs, tt.wantOuts) {
+				t.Errorf("听牌 = %v（补牌 %v），期望 %v（补牌 %v）", draws, outs, tt.wantDraws, tt.wantOuts)
+			}
+			if len(analysis.Outs) != tt.wantTotal {
+				t.Errorf("补牌数 = %d，期望 %d: %v", len(analysis.Outs), tt.wantTotal, analysis.Outs)
+			}
+			if math.Abs(analysis.NextCard-tt.wantNext) > 1e-9 || math.Abs(analysis.ByRiver-tt.wantRiver) > 1e-9 {
+				t.Errorf("NextCard = %.4f, ByRiver = %.4f，期望 %.4f, %.4f", analysis.NextCard, analysis.ByRiver, tt.wantNext, tt.wantRiver)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDrawsInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		gameType GameType
+		hole     string
+		board    string
+	}{
+		{name: "奥马哈暂不支持", gameType: PotLimitOmaha, hole: "AhKh2c3c", board: "7h2h9c"},
+		{name: "公共牌不足三张", gameType: TexasHoldem, hole: "AhKh", board: "7h2h"},
+		{name: "河牌圈不再分析", gameType: TexasHoldem, hole: "AhKh", board: "7h2h9cTdJd"},
+		{name: "重复的牌", gameType: TexasHoldem, hole: "AhKh", board: "Ah2h9c"},
+		{name: "短牌中没有2", gameType: ShortDeckHoldem, hole: "AhKh", board: "7h2h9c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hole, board := parseTestCards(t, tt.hole), parseTestCards(t, tt.board)
+			if _, err := tt.gameType.AnalyzeDraws(hole, board); err == nil {
+				t.Errorf("期望返回错误")
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+// 胜率计算
+// 作用：计算两手或多手已知底牌在部分或空白公共牌下的胜/平/负概率，组合数可接受时精确枚举，否则使用蒙特卡洛模拟
+
+package equity
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"texas-poker-backend/internal/game/poker"
+)
+
+const (
+	HoleCards  = 2 // 德州扑克每手底牌张数（范围计算只支持德州扑克）
+	BoardCards = 5 // 完整公共牌张数
+
+	DefaultExactLimit = 1000000 // 精确枚举需要的评估次数（公共牌组合数×手数×每手的评估次数）不超过该值时精确枚举
+	DefaultIterations = 20000   // 蒙特卡洛模拟的默认次数
+)
+
+// Request 胜率计算请求
+type Request struct {
+	GameType   poker.GameType // 玩法（决定底牌张数和评估方式），为空时为德州扑克
+	Hands      [][]poker.Card // 参与比较的底牌（至少两手）
+	Board      []poker.Card   // 已发出的公共牌（0~5张）
+	Dead       []poker.Card   // 已知不会再出现的牌（如已弃掉的牌）
+	ExactLimit int            // 精确枚举的评估次数上限，0表示使用默认值
+	Iterations int            // 蒙特卡洛模拟次数，0表示使用默认值
+}
+
+// HandEquity 一手底牌的胜率统计
+type HandEquity struct {
+	Cards  []poker.Card `json:"cards"`
+	Wins   int          `json:"wins"`   // 独赢的公共牌数（高低牌玩法中为通吃）
+	Ties   int          `json:"ties"`   // 平分的公共牌数（高低牌玩法中为分得部分底池）
+	Losses int          `json:"losses"` // 输掉的公共牌数
+	Win    float64      `json:"win"`    // 独赢概率
+	Tie    float64      `json:"tie"`    // 平分概率
+	Lose   float64      `json:"lose"`   // 输掉的概率
+	Equity float64      `json:"equity"` // 期望分得的底池比例（独赢加上平分时的份额）
+}
+
+// Result 胜率计算结果
+type Result struct {
+	Hands  []HandEquity `json:"hands"`
+	Boards int          `json:"boards"` // 评估的公共牌数
+	Exact  bool         `json:"exact"`  // 是否为精确枚举
+}
+
+// Calculate 计算每手底牌的胜/平/负概率
+func Calculate(req Request) (*Result, error) {
+	if req.GameType == "" {
+		req.GameType = poker.TexasHoldem
+	}
+
+	deck, err := remainingDeck(req)
+	if err != nil {
+		return nil, err
+	}
+
+	exactLimit := req.ExactLimit
+	if exactLimit <= 0 {
+		exactLimit = DefaultExactLimit
+	}
+	iterations := req.Iterations
+	if iterations <= 0 {
+		iterations = DefaultIterations
+	}
+
+	c := newCalculator(req.GameType, len(req.Hands), req.Board)
+	for i, hand := range req.Hands {
+		c.setHand(i, hand)
+	}
+	missing := BoardCards - len(req.Board)
+	result := &Result{}
+
+	workload := combinations(len(deck), missing) * uint64(len(req.Hands)) * evaluations(req.GameType)
+	if workload <= uint64(exactLimit) {
+		result.Exact = true
+		enumerate(deck, missing, c.score)
+	} else {
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+		sample(rng, deck, missing, iterations, c.score)
+	}
+
+	result.Boards = int(c.total)
+	result.Hands = make([]HandEquity, len(req.Hands))
+	for i, hand := range req.Hands {
+		win, tie, lose, equity := c.rates(i)
+		result.Hands[i] = HandEquity{
+			Cards:  hand,
+			Wins:   int(c.wins[i]),
+			Ties:   int(c.ties[i]),
+			Losses: int(c.losses[i]),
+			Win:    win,
+			Tie:    tie,
+			Lose:   lose,
+			Equity: equity,
+		}
+	}
+	return result, nil
+}
+
+// remainingDeck 校验请求中的牌，返回除底牌、公共牌和死牌外剩余的牌
+func remainingDeck(req Request) ([]poker.Card, error) {
+	if len(req.Hands) < 2 {
+		return nil, fmt.Errorf("至少需要两手底牌才能计算胜率")
+	}
+	if len(req.Board) > BoardCards {
+		return nil, fmt.Errorf("公共牌最多%d张，实际为%d张", BoardCards, len(req.Board))
+	}
+
+	holeCards := req.GameType.HoleCards()
+	for i, hand := range req.Hands {
+		if len(hand) != holeCards {
+			return nil, fmt.Errorf("第%d手底牌必须是%d张，实际为%d张", i+1, holeCards, len(hand))
+		}
+	}
+
+	groups := append(append([][]poker.Card{}, req.Hands...), req.Board, req.Dead)
+	used, err := markUsed(groups...)
+	if err != nil {
+		return nil, err
+	}
+
+	deckType := req.GameType.DeckType()
+	for card := range used {
+		if !deckType.Contains(card) {
+			return nil, fmt.Errorf("%s中没有%s", deckType, card)
+		}
+	}
+
+	deck := make([]poker.Card, 0, deckType.Size())
+	for _, card := range deckType.Cards() {
+		if !used[card] {
+			deck = append(deck, card)
+		}
+	}
+	if len(deck) < BoardCards-len(req.Board) {
+		return nil, fmt.Errorf("剩余的牌不足以发完公共牌")
+	}
+	return deck, nil
+}
+
+// markUsed 校验牌是否有效且互不重复，返回所有用到的牌
+func markUsed(groups ...[]poker.Card) (map[poker.Card]bool, error) {
+	used := make(map[poker.Card]bool)
+	for _, cards := range groups {
+		for _, card := range cards {
+			if !card.IsValid() {
+				return nil, fmt.Errorf("无效的扑克牌: suit=%d rank=%d", card.Suit, card.Rank)
+			}
+			if used[card] {
+				return nil, fmt.Errorf("重复的扑克牌: %s", card)
+			}
+			used[card] = true
+		}
+	}
+	return used, nil
+}
+
+// calculator 逐个公共牌组合评估并按权重统计胜负（复用缓冲区，评估过程不分配内存）
+type calculator struct {
+	gameType  poker.GameType
+	holes     [][]poker.Card // 每个位置的底牌
+	board     []poker.Card   // 公共牌（已知的在前，补齐的在后）
+	known     int            // 已知公共牌的张数
+	strengths []poker.HandStrength
+	lows      []poker.LowStrength // 高低牌玩法中每个位置的低牌强度
+	weight    float64             // 当前底牌组合的权重
+
+	wins, ties, losses, shares []float64 // 按权重累计的独赢、平分、输掉次数和平分份额
+	total                      float64   // 按权重累计的评估次数
+}
+
+// newCalculator 创建统计器，预先填入已知公共牌
+func newCalculator(gameType poker.GameType, seats int, board []poker.Card) *calculator {
+	c := &calculator{
+		gameType:  gameType,
+		holes:     make([][]poker.Card, seats),
+		board:     make([]poker.Card, BoardCards),
+		known:     len(board),
+		strengths: make([]poker.HandStrength, seats),
+		lows:      make([]poker.LowStrength, seats),
+		weight:    1,
+		wins:      make([]float64, seats),
+		ties:      make([]float64, seats),
+		losses:    make([]float64, seats),
+		shares:    make([]float64, seats),
+	}
+	for i := range c.holes {
+		c.holes[i] = make([]poker.Card, gameType.HoleCards())
+	}
+	copy(c.board, board)
+	return c
+}
+
+// setHand 设置某个位置的底牌
+func (c *calculator) setHand(seat int, hole []poker.Card) {
+	copy(c.holes[seat], hole)
+}
+
+// score 用补齐的公共牌评估所有位置的手牌并记录胜负
+func (c *calculator) score(runout []poker.Card) {
+	copy(c.board[c.known:], runout)
+	if c.gameType.HiLo() {
+		c.scoreHiLo()
+		return
+	}
+
+	best := poker.HandStrength(0)
+	winners := 0
+	for i, hole := range c.holes {
+		strength := c.gameType.Strength(hole, c.board)
+		c.strengths[i] = strength
+		switch {
+		case strength > best:
+			best = strength
+			winners = 1
+		case strength == best:
+			winners++
+		}
+	}
+
+	for i, strength := range c.strengths {
+		switch {
+		case strength != best:
+			c.losses[i] += c.weight
+		case winners == 1:
+			c.wins[i] += c.weight
+		default:
+			c.ties[i] += c.weight
+			c.shares[i] += c.weight / float64(winners)
+		}
+	}
+	c.total += c.weight
+}
+
+// scoreHiLo 高低牌玩法的记录方式：高牌和合格低牌各得半个底池，没有合格低牌时高牌独得
+// 分得整个底池记为独赢，分得一部分记为平分，什么都没分到记为输
+func (c *calculator) scoreHiLo() {
+	best := poker.HandStrength(0)
+	bestLow := poker.LowStrength(0)
+	winners, lowWinners := 0, 0
+	for i, hole := range c.holes {
+		strength := c.gameType.Strength(hole, c.board)
+		c.strengths[i] = strength
+		switch {
+		case strength > best:
+			best = strength
+			winners = 1
+		case strength == best:
+			winners++
+		}
+
+		low := c.gameType.LowStrength(hole, c.board)
+		c.lows[i] = low
+		switch {
+		case !low.Qualified():
+		case low > bestLow:
+			bestLow = low
+			lowWinners = 1
+		case low == bestLow:
+			lowWinners++
+		}
+	}
+
+	highHalf := 1.0
+	if lowWinners > 0 {
+		highHalf = 0.5
+	}
+	for i := range c.holes {
+		share := 0.0
+		if c.strengths[i] == best {
+			share += highHalf / float64(winners)
+		}
+		if lowWinners > 0 && c.lows[i] == bestLow {
+			share += (1 - highHalf) / float64(lowWinners)
+		}
+
+		switch {
+		case share == 0:
+			c.losses[i] += c.weight
+		case share == 1:
+			c.wins[i] += c.weight
+		default:
+			c.ties[i] += c.weight
+			c.shares[i] += c.weight * share
+		}
+	}
+	c.total += c.weight
+}
+
+// rates 计算某个位置的独赢、平分、输掉概率和期望分得的底池比例
+func (c *calculator) rates(seat int) (win, tie, lose, equity float64) {
+	if c.total == 0 {
+		return 0, 0, 0, 0
+	}
+	return c.wins[seat] / c.total, c.ties[seat] / c.total, c.losses[seat] / c.total,
+		(c.wins[seat] + c.shares[seat]) / c.total
+}
+
+// enumerate 按字典序枚举从deck中取k张牌的所有组合
+func enumerate(deck []poker.Card, k int, fn func(runout []poker.Card)) {
+	indexes := make([]int, k)
+	runout := make([]poker.Card, k)
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	for {
+		for i, index := range indexes {
+			runout[i] = deck[index]
+		}
+		fn(runout)
+
+		// 找到最右边还能后移的位置
+		i := k - 1
+		for i >= 0 && indexes[i] == len(deck)-k+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		indexes[i]++
+		for j := i + 1; j < k; j++ {
+			indexes[j] = indexes[j-1] + 1
+		}
+	}
+}
+
+// sample 随机抽取k张牌作为公共牌，重复iterations次（部分Fisher-Yates洗牌）
+func sample(rng *rand.Rand, deck []poker.Card, k, iterations int, fn func(runout []poker.Card)) {
+	deck = append([]poker.Card(nil), deck...)
+	for n := 0; n < iterations; n++ {
+		for i := 0; i < k; i++ {
+			j := i + rng.Intn(len(deck)-i)
+			deck[i], deck[j] = deck[j], deck[i]
+		}
+		fn(deck[:k])
+	}
+}
+
+// evaluations 一手底牌在一个公共牌组合上评估的牌型数
+// 德州扑克和短牌直接评估全部7张牌；奥马哈需要比较两张底牌和三张公共牌的每种组合，高低牌玩法还要再评估一遍低牌
+func evaluations(gameType poker.GameType) uint64 {
+	if gameType.HoleCards() == HoleCards {
+		return 1
+	}
+	n := combinations(gameType.HoleCards(), 2) * combinations(BoardCards, 3)
+	if gameType.HiLo() {
+		n *= 2
+	}
+	return n
+}
+
+// combinations 计算组合数C(n, k)
+func combinations(n, k int) uint64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	result := uint64(1)
+	for i := 1; i <= k; i++ {
+		result = result * uint64(n-k+i) / uint64(i)
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+// 胜率计算测试
+// 作用：用已知结果校验精确枚举的胜/平/负统计，以及蒙特卡洛模拟与精确枚举的一致性
+
+package equity
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"texas-poker-backend/internal/game/poker"
+)
+
+// mustCards 解析连续书写的牌面记法（如 "AsKd"，每张牌两个字符）
+func mustCards(t *testing.T, notation string) []poker.Card {
+	t.Helper()
+	cards := make([]poker.Card, 0, len(notation)/2)
+	for i := 0; i+1 < len(notation); i += 2 {
+		card, err := poker.ParseCard(strings.ToUpper(notation[i : i+2]))
+		if err != nil {
+			t.Fatalf("ParseCard(%q): %v", notation[i:i+2], err)
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+func TestCalculateExact(t *testing.T) {
+	tests := []struct {
+		name       string
+		hands      []string
+		board      string
+		exactLimit int
+		wantBoards int
+		want       [][3]int // 每手底牌的独赢、平分、输掉次数
+		wantEquity []float64
+	}{
+		{
+			name:       "翻牌前AA对KK（不同花色）",
+			hands:      []string{"AsAh", "KdKc"},
+			exactLimit: 10000000,
+			wantBoards: 1712304, // C(48,5)
+			want:       [][3]int{{1388072, 6538, 317694}, {317694, 6538, 1388072}},
+			wantEquity: []float64{0.81255, 0.18745},
+		},
+		{
+			name:       "转牌KK成三条，AA只剩两张A",
+			hands:      []string{"AsAh", "KdKc"},
+			board:      "2c7d9hKs",
+			wantBoards: 44,
+			want:       [][3]int{{2, 0, 42}, {42, 0, 2}},
+			wantEquity: []float64{2.0 / 44, 42.0 / 44},
+		},
+		{
+			name:       "公共牌是皇家同花顺时平分",
+			hands:      []string{"2h3h", "7d8d"},
+			board:      "AcKcQcJcTc",
+			wantBoards: 1,
+			want:       [][3]int{{0, 1, 0}, {0, 1, 0}},
+			wantEquity: []float64{0.5, 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{Board: mustCards(t, tt.board), ExactLimit: tt.exactLimit}
+			for _, hand := range tt.hands {
+				req.Hands = append(req.Hands, mustCards(t, hand))
+			}
+
+			result, err := Calculate(req)
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			if !result.Exact {
+				t.Fatalf("期望精确枚举，实际为蒙特卡洛模拟")
+			}
+			if result.Boards != tt.wantBoards {
+				t.Errorf("Boards = %d，期望 %d", result.Boards, tt.wantBoards)
+			}
+			for i, hand := range result.Hands {
+				got := [3]int{hand.Wins, hand.Ties, hand.Losses}
+				if got != tt.want[i] {
+					t.Errorf("第%d手 独赢/平分/输 = %v，期望 %v", i+1, got, tt.want[i])
+				}
+				if math.Abs(hand.Equity-tt.wantEquity[i]) > 1e-4 {
+					t.Errorf("第%d手 Equity = %.5f，期望 %.5f", i+1, hand.Equity, tt.wantEquity[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateMonteCarloMatchesExact(t *testing.T) {
+	hands := [][]poker.Card{mustCards(t, "AsAh"), mustCards(t, "KdKc")}
+
+	// 翻牌前的评估次数超过默认上限，使用蒙特卡洛模拟
+	result, err := Calculate(Request{Hands: hands, Iterations: 50000})
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	if result.Exact {
+		t.Fatalf("期望蒙特卡洛模拟，实际为精确枚举")
+	}
+	if result.Boards != 50000 {
+		t.Errorf("Boards = %d，期望 50000", result.Boards)
+	}
+
+	// 50000次模拟的标准差约为0.0018，允许约5.5倍标准差的误差
+	want := []float64{0.81255, 0.18745}
+	for i, hand := range result.Hands {
+		if math.Abs(hand.Equity-want[i]) > 0.01 {
+			t.Errorf("第%d手 Equity = %.4f，与精确值 %.4f 相差过大", i+1, hand.Equity, want[i])
+		}
+		if sum := hand.Win + hand.Tie + hand.Lose; math.Abs(sum-1) > 1e-9 {
+			t.Errorf("第%d手 胜平负概率之和 = %f", i+1, sum)
+		}
+	}
+}
+
+func TestCalculateInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		hands []string
+		board string
+	}{
+		{name: "只有一手底牌", hands: []string{"AsAh"}},
+		{name: "底牌张数不对", hands: []string{"AsAh", "KdKcQh"}},
+		{name: "重复的牌", hands: []string{"AsAh", "AsKc"}},
+		{name: "公共牌超过五张", hands: []string{"AsAh", "KdKc"}, board: "2c3c4c5c7d8d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{Board: mustCards(t, tt.board)}
+			for _, hand := range tt.hands {
+				req.Hands = append(req.Hands, mustCards(t, hand))
+			}
+			if _, err := Calculate(req); err == nil {
+				t.Errorf("期望返回错误")
+			}
+		})
+	}
+}
+
+func TestCalculateOmaha(t *testing.T) {
+	tests := []struct {
+		name       string
+		hands      []string
+		board      string
+		want       [][3]int // 每手底牌的独赢、平分、输掉次数
+		wantEquity []float64
+		wantErr    bool
+	}{
+		{
+			name:       "必须用两张底牌：同花胜过两对",
+			hands:      []string{"9c2c3d4d", "AsKd5h6h"},
+			board:      "AcKcQcJcTc",
+			want:       [][3]int{{1, 0, 0}, {0, 0, 1}},
+			wantEquity: []float64{1, 0},
+		},
+		{name: "底牌只有两张", hands: []string{"AsAh", "KdKc"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{GameType: poker.PotLimitOmaha, Board: mustCards(t, tt.board)}
+			for _, hand := range tt.hands {
+				req.Hands = append(req.Hands, mustCards(t, hand))
+			}
+
+			result, err := Calculate(req)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("期望返回错误")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			for i, hand := range result.Hands {
+				got := [3]int{hand.Wins, hand.Ties, hand.Losses}
+				if got != tt.want[i] {
+					t.Errorf("第%d手 独赢/平分/输 = %v，期望 %v", i+1, got, tt.want[i])
+				}
+				if math.Abs(hand.Equity-tt.wantEquity[i]) > 1e-4 {
+					t.Errorf("第%d手 Equity = %.5f，期望 %.5f", i+1, hand.Equity, tt.wantEquity[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateShortDeckInvalid(t *testing.T) {
+	req := Request{
+		GameType: poker.ShortDeckHoldem,
+		Hands:    [][]poker.Card{mustCards(t, "AsAh"), mustCards(t, "KdKc")},
+		Board:    mustCards(t, "2c"),
+	}
+	if _, err := Calculate(req); err == nil {
+		t.Errorf("短牌中没有2，期望返回错误")
+	}
+}
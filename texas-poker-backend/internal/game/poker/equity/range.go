@@ -0,0 +1,244 @@
+// 范围胜率计算
+// 作用：计算范围对范围、具体底牌对范围的胜率，按组合权重加权并处理牌的移除效应
+
+package equity
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"texas-poker-backend/internal/game/poker"
+)
+
+// RangeRequest 范围胜率计算请求
+type RangeRequest struct {
+	Ranges     []poker.Range // 参与比较的范围（至少两个）
+	Board      []poker.Card  // 已发出的公共牌（0~5张）
+	Dead       []poker.Card  // 已知不会再出现的牌
+	ExactLimit int           // 精确枚举的评估次数（底牌组合×公共牌×范围数）上限，0表示使用默认值
+	Iterations int           // 蒙特卡洛模拟次数，0表示使用默认值
+}
+
+// RangeEquity 一个范围的胜率统计（按组合权重加权）
+type RangeEquity struct {
+	Combos int     `json:"combos"` // 移除已知牌后剩余的组合数
+	Win    float64 `json:"win"`
+	Tie    float64 `json:"tie"`
+	Lose   float64 `json:"lose"`
+	Equity float64 `json:"equity"`
+}
+
+// RangeResult 范围胜率计算结果
+type RangeResult struct {
+	Ranges  []RangeEquity `json:"ranges"`
+	Samples int           `json:"samples"` // 评估的（底牌组合，公共牌）数量
+	Exact   bool          `json:"exact"`   // 是否为精确枚举
+}
+
+// HandVsRange 计算具体底牌对一个范围的胜率，结果中第一个为底牌，第二个为范围
+// 范围中与底牌冲突的组合会先被移除
+func HandVsRange(hand []poker.Card, villain poker.Range, board, dead []poker.Card) (*RangeResult, error) {
+	if len(hand) != HoleCards {
+		return nil, fmt.Errorf("底牌必须是%d张，实际为%d张", HoleCards, len(hand))
+	}
+	return CalculateRanges(RangeRequest{
+		Ranges: []poker.Range{{poker.NewCombo(hand[0], hand[1])}, villain.Remove(hand...)},
+		Board:  board,
+		Dead:   dead,
+	})
+}
+
+// CalculateRanges 计算多个范围之间的胜率
+// 各范围先移除公共牌和死牌，再跳过彼此冲突的组合；组合数可接受时精确枚举，否则按权重随机抽样
+func CalculateRanges(req RangeRequest) (*RangeResult, error) {
+	if len(req.Ranges) < 2 {
+		return nil, fmt.Errorf("至少需要两个范围才能计算胜率")
+	}
+	if len(req.Board) > BoardCards {
+		return nil, fmt.Errorf("公共牌最多%d张，实际为%d张", BoardCards, len(req.Board))
+	}
+	if _, err := markUsed(req.Board, req.Dead); err != nil {
+		return nil, err
+	}
+
+	known := append(append([]poker.Card{}, req.Board...), req.Dead...)
+	ranges := make([]poker.Range, len(req.Ranges))
+	for i, r := range req.Ranges {
+		ranges[i] = r.Remove(known...)
+		if len(ranges[i]) == 0 {
+			return nil, fmt.Errorf("第%d个范围移除已知牌后没有剩余的组合", i+1)
+		}
+	}
+
+	exactLimit := uint64(req.ExactLimit)
+	if exactLimit == 0 {
+		exactLimit = DefaultExactLimit
+	}
+	iterations := req.Iterations
+	if iterations <= 0 {
+		iterations = DefaultIterations
+	}
+
+	// 底牌组合数×公共牌组合数×范围数不超过上限时精确枚举
+	missing := BoardCards - len(req.Board)
+	deck := make([]poker.Card, 0, poker.StandardDeck.Size())
+	for _, card := range poker.StandardDeck.Cards() {
+		if !containsCard(known, card) {
+			deck = append(deck, card)
+		}
+	}
+	workload := combinations(len(deck)-HoleCards*len(ranges), missing) * uint64(len(ranges))
+	for _, r := range ranges {
+		workload *= uint64(len(r))
+		if workload > exactLimit {
+			break
+		}
+	}
+
+	c := newCalculator(poker.TexasHoldem, len(ranges), req.Board)
+	result := &RangeResult{Exact: workload <= exactLimit}
+	score := func(runout []poker.Card) {
+		c.score(runout)
+		result.Samples++
+	}
+
+	if result.Exact {
+		enumerateRanges(c, ranges, deck, missing, score)
+	} else {
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+		if err := sampleRanges(c, rng, ranges, deck, missing, iterations, score); err != nil {
+			return nil, err
+		}
+	}
+	if result.Samples == 0 {
+		return nil, fmt.Errorf("各范围之间没有可以同时成立的组合")
+	}
+
+	result.Ranges = make([]RangeEquity, len(ranges))
+	for i, r := range ranges {
+		win, tie, lose, equity := c.rates(i)
+		result.Ranges[i] = RangeEquity{
+			Combos: len(r),
+			Win:    win,
+			Tie:    tie,
+			Lose:   lose,
+			Equity: equity,
+		}
+	}
+	return result, nil
+}
+
+// enumerateRanges 枚举所有互不冲突的底牌组合，每组再枚举剩余公共牌，以组合权重之积加权
+func enumerateRanges(c *calculator, ranges []poker.Range, deck []poker.Card, missing int, score func([]poker.Card)) {
+	picked := make([]poker.Combo, len(ranges))
+	remaining := make([]poker.Card, 0, len(deck))
+
+	var walk func(seat int, weight float64)
+	walk = func(seat int, weight float64) {
+		if seat == len(ranges) {
+			remaining = withoutCombos(remaining[:0], deck, picked)
+			c.weight = weight
+			enumerate(remaining, missing, score)
+			return
+		}
+		for _, combo := range ranges[seat] {
+			if conflicts(combo, picked[:seat]) {
+				continue
+			}
+			picked[seat] = combo
+			c.setHand(seat, combo.Cards[:])
+			walk(seat+1, weight*combo.Weight)
+		}
+	}
+	walk(0, 1)
+}
+
+// sampleRanges 按权重随机抽取互不冲突的底牌组合和剩余公共牌，重复iterations次
+func sampleRanges(c *calculator, rng *rand.Rand, ranges []poker.Range, deck []poker.Card, missing, iterations int, score func([]poker.Card)) error {
+	// 每个范围的累计权重，用于按权重抽取组合
+	cumulative := make([][]float64, len(ranges))
+	for i, r := range ranges {
+		total := 0.0
+		cumulative[i] = make([]float64, len(r))
+		for j, combo := range r {
+			total += combo.Weight
+			cumulative[i][j] = total
+		}
+	}
+
+	picked := make([]poker.Combo, len(ranges))
+	remaining := make([]poker.Card, 0, len(deck))
+	c.weight = 1
+
+	// 冲突的抽样直接丢弃，连续失败过多说明范围之间几乎没有可以同时成立的组合
+	maxAttempts := iterations * 100
+	for done, attempts := 0, 0; done < iterations; attempts++ {
+		if attempts >= maxAttempts {
+			return fmt.Errorf("各范围之间没有可以同时成立的组合")
+		}
+
+		ok := true
+		for seat, r := range ranges {
+			weights := cumulative[seat]
+			index := sort.SearchFloat64s(weights, rng.Float64()*weights[len(weights)-1])
+			combo := r[min(index, len(r)-1)]
+			if conflicts(combo, picked[:seat]) {
+				ok = false
+				break
+			}
+			picked[seat] = combo
+			c.setHand(seat, combo.Cards[:])
+		}
+		if !ok {
+			continue
+		}
+
+		remaining = withoutCombos(remaining[:0], deck, picked)
+		for i := 0; i < missing; i++ {
+			j := i + rng.Intn(len(remaining)-i)
+			remaining[i], remaining[j] = remaining[j], remaining[i]
+		}
+		score(remaining[:missing])
+		done++
+	}
+	return nil
+}
+
+// conflicts 判断组合是否与已选的组合有相同的牌
+func conflicts(combo poker.Combo, picked []poker.Combo) bool {
+	for _, other := range picked {
+		if combo.Conflicts(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutCombos 将deck中不属于任何已选组合的牌追加到dst
+func withoutCombos(dst, deck []poker.Card, picked []poker.Combo) []poker.Card {
+	for _, card := range deck {
+		blocked := false
+		for _, combo := range picked {
+			if combo.Contains(card) {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			dst = append(dst, card)
+		}
+	}
+	return dst
+}
+
+// containsCard 判断牌是否在列表中
+func containsCard(cards []poker.Card, card poker.Card) bool {
+	for _, c := range cards {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
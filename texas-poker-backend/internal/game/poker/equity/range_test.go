@@ -0,0 +1,149 @@
+// 范围胜率计算测试
+// 作用：用已知结果校验范围对范围、具体底牌对范围的胜率，以及牌的移除效应和冲突组合的处理
+
+package equity
+
+import (
+	"math"
+	"testing"
+
+	"texas-poker-backend/internal/game/poker"
+)
+
+// mustRange 解析范围写法
+func mustRange(t *testing.T, notation string) poker.Range {
+	t.Helper()
+	r, err := poker.ParseRange(notation)
+	if err != nil {
+		t.Fatalf("ParseRange(%q): %v", notation, err)
+	}
+	return r
+}
+
+func TestHandVsRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		hand        string
+		villain     string
+		board       string
+		wantCombos  int
+		wantSamples int
+		wantEquity  float64
+	}{
+		{
+			// 公共牌的Ks和底牌都不在对手范围中，剩下KhKd、KhKc、KdKc三种组合都是三条K
+			name:        "转牌对手范围都是三条，AA只能靠两张A",
+			hand:        "AsAh",
+			villain:     "KK",
+			board:       "2c7d9hKs",
+			wantCombos:  3,
+			wantSamples: 3 * 44,
+			wantEquity:  2.0 / 44,
+		},
+		{
+			// 对手范围中包含As或Ah的组合被移除，只剩AdAc，河牌总是平分
+			name:        "底牌移除对手范围中的组合",
+			hand:        "AsAh",
+			villain:     "AA",
+			board:       "2c7d9hJs",
+			wantCombos:  1,
+			wantSamples: 44,
+			wantEquity:  0.5,
+		},
+		{
+			name:        "公共牌已发完时按组合权重加权",
+			hand:        "AsAh",
+			villain:     "KdKc, QdQc:0.5",
+			board:       "2c7d9hJsKs",
+			wantCombos:  2,
+			wantSamples: 2,
+			wantEquity:  0.5 / 1.5, // KK三条获胜（权重1），QQ输（权重0.5）
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := HandVsRange(mustCards(t, tt.hand), mustRange(t, tt.villain), mustCards(t, tt.board), nil)
+			if err != nil {
+				t.Fatalf("HandVsRange: %v", err)
+			}
+			if !result.Exact {
+				t.Fatalf("期望精确枚举，实际为蒙特卡洛模拟")
+			}
+			if result.Samples != tt.wantSamples {
+				t.Errorf("Samples = %d，期望 %d", result.Samples, tt.wantSamples)
+			}
+			if got := result.Ranges[1].Combos; got != tt.wantCombos {
+				t.Errorf("对手范围组合数 = %d，期望 %d", got, tt.wantCombos)
+			}
+			hero, villain := result.Ranges[0].Equity, result.Ranges[1].Equity
+			if math.Abs(hero-tt.wantEquity) > 1e-9 {
+				t.Errorf("底牌 Equity = %.5f，期望 %.5f", hero, tt.wantEquity)
+			}
+			if math.Abs(hero+villain-1) > 1e-9 {
+				t.Errorf("两边 Equity 之和 = %f，期望 1", hero+villain)
+			}
+		})
+	}
+}
+
+func TestCalculateRanges(t *testing.T) {
+	// 具体组合的范围对范围应与具体底牌的胜率完全一致
+	exact, err := Calculate(Request{
+		Hands: [][]poker.Card{mustCards(t, "AsAh"), mustCards(t, "KdKc")},
+		Board: mustCards(t, "2c7d9h"),
+	})
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	ranged, err := CalculateRanges(RangeRequest{
+		Ranges: []poker.Range{mustRange(t, "AsAh"), mustRange(t, "KdKc")},
+		Board:  mustCards(t, "2c7d9h"),
+	})
+	if err != nil {
+		t.Fatalf("CalculateRanges: %v", err)
+	}
+	for i := range exact.Hands {
+		if math.Abs(exact.Hands[i].Equity-ranged.Ranges[i].Equity) > 1e-9 {
+			t.Errorf("第%d个范围 Equity = %.6f，与具体底牌的 %.6f 不一致", i+1, ranged.Ranges[i].Equity, exact.Hands[i].Equity)
+		}
+	}
+
+	// 相同的范围互相比较时胜率对称
+	symmetric, err := CalculateRanges(RangeRequest{
+		Ranges: []poker.Range{mustRange(t, "KK"), mustRange(t, "KK")},
+		Board:  mustCards(t, "2c7d9hJs"),
+	})
+	if err != nil {
+		t.Fatalf("CalculateRanges: %v", err)
+	}
+	if math.Abs(symmetric.Ranges[0].Equity-0.5) > 1e-9 || math.Abs(symmetric.Ranges[1].Equity-0.5) > 1e-9 {
+		t.Errorf("KK对KK Equity = %.6f / %.6f，期望各 0.5", symmetric.Ranges[0].Equity, symmetric.Ranges[1].Equity)
+	}
+}
+
+func TestCalculateRangesInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges []string
+		board  string
+		dead   string
+	}{
+		{name: "只有一个范围", ranges: []string{"AA"}},
+		{name: "范围被公共牌全部移除", ranges: []string{"AsAh", "KK"}, board: "As2c3d"},
+		{name: "范围之间没有可以同时成立的组合", ranges: []string{"AsAh", "AsKd"}},
+		{name: "公共牌与死牌重复", ranges: []string{"AA", "KK"}, board: "2c3d4h", dead: "2c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := RangeRequest{Board: mustCards(t, tt.board), Dead: mustCards(t, tt.dead)}
+			for _, notation := range tt.ranges {
+				req.Ranges = append(req.Ranges, mustRange(t, notation))
+			}
+			if _, err := CalculateRanges(req); err == nil {
+				t.Errorf("期望返回错误")
+			}
+		})
+	}
+}
@@ -0,0 +1,459 @@
+// 手牌评估性能测试
+// 作用：对比查表评估器与原先枚举21种五张牌组合的评估实现的性能，并校验两者的评估结果一致
+
+package poker
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// benchHands 预先生成的随机7张牌（避免发牌开销计入评估耗时）
+func benchHands(n int) [][]Card {
+	rng := rand.New(rand.NewSource(1))
+	deck := NewDeck().GetAllCards()
+	hands := make([][]Card, n)
+	for i := range hands {
+		rng.Shuffle(len(deck), func(a, b int) { deck[a], deck[b] = deck[b], deck[a] })
+		hands[i] = append([]Card(nil), deck[:7]...)
+	}
+	return hands
+}
+
+// BenchmarkEvaluate 查表评估（只计算强度，不分配内存）
+func BenchmarkEvaluate(b *testing.B) {
+	hands := benchHands(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Evaluate(hands[i&1023])
+	}
+}
+
+// BenchmarkEvaluateHand 查表评估并选出最佳五张牌
+func BenchmarkEvaluateHand(b *testing.B) {
+	hands := benchHands(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EvaluateHand(hands[i&1023])
+	}
+}
+
+// BenchmarkLegacyEvaluateHand 原先的枚举组合实现
+func BenchmarkLegacyEvaluateHand(b *testing.B) {
+	hands := benchHands(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		legacyEvaluateHand(hands[i&1023])
+	}
+}
+
+// TestEvaluateMatchesLegacy 随机手牌和边界牌型上，查表评估与原先实现的牌型及两两之间的大小关系一致
+func TestEvaluateMatchesLegacy(t *testing.T) {
+	hands := benchHands(10000)
+	for _, notation := range []string{
+		"AsKsQsJsTs9s8s", // 皇家同花顺
+		"5h4h3h2hAh9c9d", // 最小的同花顺
+		"As2d3c4h5s9dJc", // A当作1的顺子
+		"AsKdQcJhTs2d3c", // 最大的顺子
+		"9s9d9c9hKsKdKc", // 四条带三条
+		"KsKdKcQsQdQc2h", // 两个三条
+		"AsAdKsKdQsQd2c", // 三个对子
+		"As3s5s7s9sJsKs", // 七张同花
+		"2s3d4c5h7s8d9c", // 没有顺子的散牌
+	} {
+		hands = append(hands, parseTestCards(t, notation))
+	}
+	for i := 0; i < 1000; i++ {
+		hands = append(hands, hands[i][:5], hands[i][:6])
+	}
+
+	legacy := make([]Hand, len(hands))
+	current := make([]Hand, len(hands))
+	for i, cards := range hands {
+		hand, err := EvaluateHand(cards)
+		if err != nil {
+			t.Fatalf("EvaluateHand(%s): %v", Cards(cards), err)
+		}
+		legacy[i] = legacyEvaluateHand(cards)
+		current[i] = hand
+		if current[i].Type != legacy[i].Type {
+			t.Fatalf("%s: 牌型为%v，原先的实现为%v", Cards(cards), current[i].Type, legacy[i].Type)
+		}
+		if Evaluate(cards) != current[i].Strength {
+			t.Fatalf("%s: Evaluate与EvaluateHand的强度不一致", Cards(cards))
+		}
+	}
+
+	for i := 1; i < len(hands); i++ {
+		got := CompareHands(current[i-1], current[i])
+		want := legacy[i-1].Strength.Compare(legacy[i].Strength)
+		if got != want {
+			t.Fatalf("%s 与 %s: 比较结果为%d，原先的实现为%d", Cards(hands[i-1]), Cards(hands[i]), got, want)
+		}
+	}
+}
+
+// parseTestCards 解析连续书写的牌面记法（如 "AsKd"，每张牌两个字符）
+func parseTestCards(t *testing.T, notation string) []Card {
+	t.Helper()
+	cards := make([]Card, 0, len(notation)/2)
+	for i := 0; i+1 < len(notation); i += 2 {
+		card, err := ParseCard(strings.ToUpper(notation[i : i+2]))
+		if err != nil {
+			t.Fatalf("ParseCard(%q): %v", notation[i:i+2], err)
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// 以下为原先的评估实现，仅用于性能对比和结果校验
+
+// legacyEvaluateHand 枚举21种五张牌组合，逐一判断牌型后取最大
+func legacyEvaluateHand(cards []Card) Hand {
+	var bestHand Hand
+	for i, combination := range generateCombinations(cards, 5) {
+		hand := evaluateFiveCards(combination)
+		hand.Strength = NewHandStrength(hand.Type, hand.Ranks)
+		if i == 0 || hand.Strength > bestHand.Strength {
+			bestHand = hand
+		}
+	}
+	return bestHand
+}
+
+// evaluateFiveCards 评估5张牌的牌型
+func evaluateFiveCards(cards []Card) Hand {
+	// 复制并排序卡牌
+	sortedCards := Cards(cards).Clone()
+	sortedCards.Sort()
+
+	// 检查各种牌型（按强度从高到低）
+	if hand := checkRoyalFlush(sortedCards); hand.Type != -1 {
+		return hand
+	}
+	if hand := checkStraightFlush(sortedCards); hand.Type != -1 {
+		return hand
+	}
+	if hand := checkFourOfAKind(sortedCards); hand.Type != -1 {
+		return hand
+	}
+	if hand := checkFullHouse(sortedCards); hand.Type != -1 {
+		return hand
+	}
+	if hand := checkFlush(sortedCards); hand.Type != -1 {
+		return hand
+	}
+	if hand := checkStraight(sortedCards); hand.Type != -1 {
+		return hand
+	}
+	if hand := checkThreeOfAKind(sortedCards); hand.Type != -1 {
+		return hand
+	}
+	if hand := checkTwoPair(sortedCards); hand.Type != -1 {
+		return hand
+	}
+	if hand := checkOnePair(sortedCards); hand.Type != -1 {
+		return hand
+	}
+
+	// 高牌
+	return checkHighCard(sortedCards)
+}
+
+// checkRoyalFlush 检查皇家同花顺（A K Q J 10同花）
+func checkRoyalFlush(cards Cards) Hand {
+	if hand := checkStraightFlush(cards); hand.Type == StraightFlush {
+		// 检查是否为A K Q J 10
+		if hand.Ranks[0] == Ace {
+			return Hand{
+				Cards: cards,
+				Type:  RoyalFlush,
+				Ranks: []Rank{Ace},
+			}
+		}
+	}
+	return Hand{Type: -1}
+}
+
+// checkStraightFlush 检查同花顺
+func checkStraightFlush(cards Cards) Hand {
+	if flushHand := checkFlush(cards); flushHand.Type == Flush {
+		if straightHand := checkStraight(cards); straightHand.Type == Straight {
+			return Hand{
+				Cards: cards,
+				Type:  StraightFlush,
+				Ranks: straightHand.Ranks,
+			}
+		}
+	}
+	return Hand{Type: -1}
+}
+
+// checkFourOfAKind 检查四条
+func checkFourOfAKind(cards Cards) Hand {
+	groups := groupRanks(getRankCounts(cards))
+
+	if len(groups[4]) > 0 {
+		// 找到四条，剩余的一张牌作为kicker
+		return Hand{
+			Cards: cards,
+			Type:  FourOfAKind,
+			Ranks: []Rank{groups[4][0], groups[1][0]},
+		}
+	}
+
+	return Hand{Type: -1}
+}
+
+// checkFullHouse 检查葫芦（三条+一对）
+func checkFullHouse(cards Cards) Hand {
+	groups := groupRanks(getRankCounts(cards))
+
+	if len(groups[3]) > 0 && len(groups[2]) > 0 {
+		return Hand{
+			Cards: cards,
+			Type:  FullHouse,
+			Ranks: []Rank{groups[3][0], groups[2][0]},
+		}
+	}
+
+	return Hand{Type: -1}
+}
+
+// checkFlush 检查同花
+func checkFlush(cards Cards) Hand {
+	suitCounts := getSuitCounts(cards)
+
+	for _, count := range suitCounts {
+		if count == 5 {
+			// 按点数降序排列
+			ranks := make([]Rank, 5)
+			for i, card := range cards {
+				ranks[i] = card.Rank
+			}
+			sort.Slice(ranks, func(i, j int) bool {
+				return ranks[i] > ranks[j]
+			})
+
+			return Hand{
+				Cards: cards,
+				Type:  Flush,
+				Ranks: ranks,
+			}
+		}
+	}
+
+	return Hand{Type: -1}
+}
+
+// checkStraight 检查顺子
+func checkStraight(cards Cards) Hand {
+	ranks := make([]Rank, len(cards))
+	for i, card := range cards {
+		ranks[i] = card.Rank
+	}
+
+	// 去重并排序
+	uniqueRanks := removeDuplicateRanks(ranks)
+	sort.Slice(uniqueRanks, func(i, j int) bool {
+		return uniqueRanks[i] > uniqueRanks[j]
+	})
+
+	// 检查是否有连续的5张牌
+	if len(uniqueRanks) >= 5 {
+		for i := 0; i <= len(uniqueRanks)-5; i++ {
+			if isConsecutive(uniqueRanks[i : i+5]) {
+				return Hand{
+					Cards: cards,
+					Type:  Straight,
+					Ranks: []Rank{uniqueRanks[i]}, // 最高牌
+				}
+			}
+		}
+	}
+
+	// 特殊情况：A-2-3-4-5（轮子）
+	if containsRanks(uniqueRanks, []Rank{Ace, Five, Four, Three, Two}) {
+		return Hand{
+			Cards: cards,
+			Type:  Straight,
+			Ranks: []Rank{Five}, // A-2-3-4-5中，5是最高牌
+		}
+	}
+
+	return Hand{Type: -1}
+}
+
+// checkThreeOfAKind 检查三条
+func checkThreeOfAKind(cards Cards) Hand {
+	groups := groupRanks(getRankCounts(cards))
+
+	if len(groups[3]) > 0 {
+		// 找到三条，剩余的两张牌作为kicker（已按点数降序排列）
+		return Hand{
+			Cards: cards,
+			Type:  ThreeOfAKind,
+			Ranks: append([]Rank{groups[3][0]}, groups[1]...),
+		}
+	}
+
+	return Hand{Type: -1}
+}
+
+// checkTwoPair 检查两对
+func checkTwoPair(cards Cards) Hand {
+	groups := groupRanks(getRankCounts(cards))
+
+	if len(groups[2]) == 2 {
+		// 对子已按点数降序排列
+		return Hand{
+			Cards: cards,
+			Type:  TwoPair,
+			Ranks: []Rank{groups[2][0], groups[2][1], groups[1][0]},
+		}
+	}
+
+	return Hand{Type: -1}
+}
+
+// checkOnePair 检查一对
+func checkOnePair(cards Cards) Hand {
+	groups := groupRanks(getRankCounts(cards))
+
+	if len(groups[2]) > 0 {
+		// 找到对子，剩余的三张牌作为kicker（已按点数降序排列）
+		return Hand{
+			Cards: cards,
+			Type:  OnePair,
+			Ranks: append([]Rank{groups[2][0]}, groups[1]...),
+		}
+	}
+
+	return Hand{Type: -1}
+}
+
+// checkHighCard 高牌
+func checkHighCard(cards Cards) Hand {
+	ranks := make([]Rank, len(cards))
+	for i, card := range cards {
+		ranks[i] = card.Rank
+	}
+
+	// 按点数降序排列
+	sort.Slice(ranks, func(i, j int) bool {
+		return ranks[i] > ranks[j]
+	})
+
+	return Hand{
+		Cards: cards,
+		Type:  HighCard,
+		Ranks: ranks,
+	}
+}
+
+// 辅助函数
+
+// rankCounts 每个点数的出现次数（按点数索引）
+type rankCounts [Ace + 1]int
+
+// getRankCounts 获取每个点数的出现次数
+func getRankCounts(cards Cards) rankCounts {
+	var counts rankCounts
+	for _, card := range cards {
+		counts[card.Rank]++
+	}
+	return counts
+}
+
+// groupRanks 按出现次数分组点数，每组内按点数从大到小排列（保证踢脚的选择是确定的）
+func groupRanks(counts rankCounts) [5][]Rank {
+	var groups [5][]Rank
+	for rank := Ace; rank >= Two; rank-- {
+		if count := counts[rank]; count > 0 {
+			groups[count] = append(groups[count], rank)
+		}
+	}
+	return groups
+}
+
+// getSuitCounts 获取每个花色的出现次数
+func getSuitCounts(cards Cards) map[Suit]int {
+	counts := make(map[Suit]int)
+	for _, card := range cards {
+		counts[card.Suit]++
+	}
+	return counts
+}
+
+// removeDuplicateRanks 去除重复的点数
+func removeDuplicateRanks(ranks []Rank) []Rank {
+	keys := make(map[Rank]bool)
+	var result []Rank
+
+	for _, rank := range ranks {
+		if !keys[rank] {
+			keys[rank] = true
+			result = append(result, rank)
+		}
+	}
+
+	return result
+}
+
+// isConsecutive 检查点数是否连续
+func isConsecutive(ranks []Rank) bool {
+	for i := 1; i < len(ranks); i++ {
+		if ranks[i-1]-ranks[i] != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// containsRanks 检查是否包含指定的点数
+func containsRanks(ranks []Rank, target []Rank) bool {
+	rankMap := make(map[Rank]bool)
+	for _, rank := range ranks {
+		rankMap[rank] = true
+	}
+
+	for _, rank := range target {
+		if !rankMap[rank] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// generateCombinations 生成所有可能的组合
+func generateCombinations(cards []Card, k int) [][]Card {
+	var result [][]Card
+	var combination []Card
+
+	var backtrack func(start int)
+	backtrack = func(start int) {
+		if len(combination) == k {
+			// 复制当前组合
+			combo := make([]Card, k)
+			copy(combo, combination)
+			result = append(result, combo)
+			return
+		}
+
+		for i := start; i < len(cards); i++ {
+			combination = append(combination, cards[i])
+			backtrack(i + 1)
+			combination = combination[:len(combination)-1]
+		}
+	}
+
+	backtrack(0)
+	return result
+}
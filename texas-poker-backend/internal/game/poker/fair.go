@@ -0,0 +1,120 @@
+// 可验证公平洗牌
+// 作用：实现服务器种子承诺—揭示流程，将服务器种子与玩家的客户端种子混合后确定性地推导出牌堆顺序
+//
+// 验证方法：
+//  1. SHA-256(服务器种子) 的十六进制结果应等于开局前公布的承诺
+//  2. 组合种子 = HMAC-SHA256(密钥=服务器种子, 消息=按发牌顺序用"\n"连接的客户端种子)
+//  3. 第i个随机块 = SHA-256(组合种子 || i的8字节大端表示)，每块依次切出4个大端uint64
+//  4. 从标准顺序的牌堆（黑桃、红桃、方块、梅花，每种花色2到A共52张；短牌每种花色6到A共36张）开始做Fisher-Yates洗牌：
+//     i从张数-1递减到1，依次取随机数v，丢弃不小于 M - M mod (i+1) 的值（M = 2^64-1），
+//     j = v mod (i+1)，交换第i和第j张
+
+package poker
+
+import (
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MaxClientSeedLength 客户端种子的最大长度
+const MaxClientSeedLength = 64
+
+// GenerateServerSeed 生成32字节的随机服务器种子（十六进制）
+func GenerateServerSeed() string {
+	return randomHex(32)
+}
+
+// GenerateClientSeed 生成默认的客户端种子（玩家没有设置时使用）
+func GenerateClientSeed() string {
+	return randomHex(16)
+}
+
+// randomHex 生成n字节的随机数并编码为十六进制
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := cryptorand.Read(buf); err != nil {
+		panic("读取系统随机数失败: " + err.Error())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// HashSeed 计算服务器种子的承诺值（SHA-256的十六进制）
+func HashSeed(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateClientSeed 检查客户端种子（1~64个字母、数字、"-"或"_"）
+func ValidateClientSeed(seed string) error {
+	if seed == "" || len(seed) > MaxClientSeedLength {
+		return fmt.Errorf("客户端种子长度必须在1到%d之间", MaxClientSeedLength)
+	}
+	for _, ch := range seed {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '_') {
+			return fmt.Errorf("客户端种子只能包含字母、数字、-和_")
+		}
+	}
+	return nil
+}
+
+// CombineSeeds 将服务器种子与按发牌顺序排列的客户端种子混合为组合种子
+func CombineSeeds(serverSeed string, clientSeeds []string) []byte {
+	mac := hmac.New(sha256.New, []byte(serverSeed))
+	mac.Write([]byte(strings.Join(clientSeeds, "\n")))
+	return mac.Sum(nil)
+}
+
+// seedRNG 由组合种子通过SHA-256计数器模式生成的确定性随机数源
+type seedRNG struct {
+	seed    []byte
+	counter uint64
+	block   [sha256.Size]byte
+	offset  int // 当前块中已使用的字节数
+}
+
+// NewSeedRNG 创建由组合种子确定的随机数源（相同种子总是产生相同的序列）
+func NewSeedRNG(seed []byte) RNG {
+	return &seedRNG{seed: append([]byte(nil), seed...), offset: sha256.Size}
+}
+
+// next 取出下一个64位随机数
+func (s *seedRNG) next() uint64 {
+	if s.offset+8 > len(s.block) {
+		var counter [8]byte
+		binary.BigEndian.PutUint64(counter[:], s.counter)
+		s.block = sha256.Sum256(append(append([]byte(nil), s.seed...), counter[:]...))
+		s.counter++
+		s.offset = 0
+	}
+	value := binary.BigEndian.Uint64(s.block[s.offset:])
+	s.offset += 8
+	return value
+}
+
+// Intn 通过拒绝采样消除取模偏差
+func (s *seedRNG) Intn(n int) int {
+	if n <= 0 {
+		panic("随机数范围必须大于0")
+	}
+
+	bound := uint64(n)
+	limit := math.MaxUint64 - math.MaxUint64%bound
+	for {
+		if value := s.next(); value < limit {
+			return int(value % bound)
+		}
+	}
+}
+
+// NewFairDeck 由服务器种子和客户端种子推导出洗好的指定类型牌堆
+func NewFairDeck(deckType DeckType, serverSeed string, clientSeeds []string) *Deck {
+	deck := NewDeckOfType(deckType, NewSeedRNG(CombineSeeds(serverSeed, clientSeeds)))
+	deck.Shuffle()
+	return deck
+}
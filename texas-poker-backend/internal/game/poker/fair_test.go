@@ -0,0 +1,93 @@
+// 可验证公平洗牌测试
+// 作用：用按文件头验证方法独立算出的结果校验种子承诺、种子混合和推导出的牌堆顺序，以及客户端种子的校验规则
+
+package poker
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestHashSeed(t *testing.T) {
+	tests := []struct {
+		seed string
+		want string
+	}{
+		{seed: "abc", want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{seed: "server-seed", want: "91024ec49c5bec0b689e42892526320fce08337205c91de94c7a588c20d08eeb"},
+	}
+
+	for _, tt := range tests {
+		if got := HashSeed(tt.seed); got != tt.want {
+			t.Errorf("HashSeed(%q) = %s，期望 %s", tt.seed, got, tt.want)
+		}
+	}
+}
+
+func TestFairDeck(t *testing.T) {
+	tests := []struct {
+		name         string
+		clientSeeds  []string
+		wantCombined string
+		want         string // 牌堆的前10张牌
+	}{
+		{
+			name:         "两位玩家",
+			clientSeeds:  []string{"alice", "bob"},
+			wantCombined: "8540748ef820bf9102c24a392619cb9589684ffd4f0b21b6a5ae4b995c8aa5bc",
+			want:         "7hQd2hJd6d7cJc9hJs3c",
+		},
+		{
+			name:         "发牌顺序不同牌堆也不同",
+			clientSeeds:  []string{"bob", "alice"},
+			wantCombined: "9d018b9578c02d8b06d381dcf3a12c26795b56a58aa361a732e1d6e51488d2eb",
+			want:         "3c5cQhKsQs2hAh5hAd6h",
+		},
+		{
+			name:         "没有客户端种子",
+			clientSeeds:  nil,
+			wantCombined: "50e6c205e84a85a502e54800ed4010e622e5d425cf6122125573a61c532d3adb",
+			want:         "8h6d9d2c8dAhAc8c4hJd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hex.EncodeToString(CombineSeeds("server-seed", tt.clientSeeds)); got != tt.wantCombined {
+				t.Errorf("CombineSeeds = %s，期望 %s", got, tt.wantCombined)
+			}
+
+			deck := NewFairDeck(StandardDeck, "server-seed", tt.clientSeeds)
+			if got, want := deck.GetAllCards()[:10], parseTestCards(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("牌堆前10张 = %v，期望 %v", got, want)
+			}
+
+			again := NewFairDeck(StandardDeck, "server-seed", tt.clientSeeds)
+			if !reflect.DeepEqual(again.GetAllCards(), deck.GetAllCards()) {
+				t.Errorf("相同的种子推导出了不同的牌堆")
+			}
+		})
+	}
+}
+
+func TestValidateClientSeed(t *testing.T) {
+	tests := []struct {
+		seed    string
+		wantErr bool
+	}{
+		{seed: "alice", wantErr: false},
+		{seed: "Lucky_Seed-2024", wantErr: false},
+		{seed: "", wantErr: true},
+		{seed: "has space", wantErr: true},
+		{seed: "种子", wantErr: true},
+		{seed: "0123456789012345678901234567890123456789012345678901234567890123", wantErr: false},
+		{seed: "01234567890123456789012345678901234567890123456789012345678901234", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateClientSeed(tt.seed); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateClientSeed(%q) error = %v，期望返回错误: %v", tt.seed, err, tt.wantErr)
+		}
+	}
+}
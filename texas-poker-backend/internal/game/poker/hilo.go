@@ -0,0 +1,239 @@
+// 高低牌（8或更小）
+// 作用：评估8或更小的合格低牌（A记为1，顺子和同花不影响低牌），并按高低牌规则拆分底池（通吃、分四分之一和零头分配）
+
+package poker
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// lowMaskLimit 低牌点数位集合的上界（A到8共8位）
+const lowMaskLimit = 1 << 8
+
+// LowStrength 低牌强度（数值越大低牌越好，0表示没有合格的低牌）
+// 编码方式：五个不同低牌点数组成的位集合（A占第0位，8占第7位）越小低牌越好，强度为上界减去该集合
+type LowStrength uint16
+
+// LowHand 低牌
+type LowHand struct {
+	Cards    []Card      `json:"cards"` // 组成低牌的五张牌（从大到小）
+	Strength LowStrength `json:"-"`
+}
+
+// Qualified 是否有合格的低牌
+func (s LowStrength) Qualified() bool {
+	return s > 0
+}
+
+// Ranks 低牌的五个点数（从大到小，A排在最后）
+func (s LowStrength) Ranks() []Rank {
+	if !s.Qualified() {
+		return nil
+	}
+
+	mask := uint16(lowMaskLimit - int(s))
+	ranks := make([]Rank, 0, 5)
+	for bit := 7; bit >= 0; bit-- {
+		if mask&(1<<bit) == 0 {
+			continue
+		}
+		if bit == 0 {
+			ranks = append(ranks, Ace)
+		} else {
+			ranks = append(ranks, Rank(bit+1))
+		}
+	}
+	return ranks
+}
+
+// String 低牌转字符串（如 "8-6-4-2-A"）
+func (s LowStrength) String() string {
+	if !s.Qualified() {
+		return "无低牌"
+	}
+
+	ranks := s.Ranks()
+	parts := make([]string, len(ranks))
+	for i, rank := range ranks {
+		parts[i] = rank.String()
+	}
+	return strings.Join(parts, "-")
+}
+
+// Qualified 是否为合格的低牌
+func (h LowHand) Qualified() bool {
+	return h.Strength.Qualified()
+}
+
+// String 低牌转字符串
+func (h LowHand) String() string {
+	return h.Strength.String()
+}
+
+// lowRankBit 点数在低牌位集合中对应的位（A为第0位，2~8为第1~7位），大于8的点数返回0
+func lowRankBit(rank Rank) uint16 {
+	switch {
+	case rank == Ace:
+		return 1
+	case rank >= Two && rank <= Eight:
+		return 1 << (rank - 1)
+	default:
+		return 0
+	}
+}
+
+// lowestBits 保留位集合中最低的n位
+func lowestBits(mask uint16, n int) uint16 {
+	var result uint16
+	for i := 0; i < n && mask != 0; i++ {
+		low := mask & -mask
+		result |= low
+		mask &^= low
+	}
+	return result
+}
+
+// lowStrength 由五个不同低牌点数的位集合计算低牌强度
+func lowStrength(mask uint16) LowStrength {
+	if bits.OnesCount16(mask) != 5 {
+		return 0
+	}
+	return LowStrength(lowMaskLimit - int(mask))
+}
+
+// pickLowCards 按低牌位集合从大到小依次取牌，优先从排在前面的牌组中取
+func pickLowCards(mask uint16, groups ...[]Card) []Card {
+	cards := make([]Card, 0, 5)
+	for bit := 7; bit >= 0; bit-- {
+		if mask&(1<<bit) == 0 {
+			continue
+		}
+	search:
+		for _, group := range groups {
+			for _, card := range group {
+				if lowRankBit(card.Rank) == 1<<bit {
+					cards = append(cards, card)
+					break search
+				}
+			}
+		}
+	}
+	return cards
+}
+
+// LowStrengthOf 计算任选五张牌组成的最好低牌（不分配内存，调用方需保证牌有效且不重复）
+func LowStrengthOf(cards []Card) LowStrength {
+	var mask uint16
+	for _, card := range cards {
+		mask |= lowRankBit(card.Rank)
+	}
+	return lowStrength(lowestBits(mask, 5))
+}
+
+// EvaluateLow 评估5~7张牌中任选五张组成的最好低牌（没有合格低牌时Strength为0）
+func EvaluateLow(cards []Card) (LowHand, error) {
+	if len(cards) < MinHandCards || len(cards) > MaxHandCards {
+		return LowHand{}, fmt.Errorf("牌数必须在%d到%d张之间，实际为%d张", MinHandCards, MaxHandCards, len(cards))
+	}
+	if err := validateCards(cards); err != nil {
+		return LowHand{}, err
+	}
+
+	strength := LowStrengthOf(cards)
+	if !strength.Qualified() {
+		return LowHand{}, nil
+	}
+	return LowHand{
+		Cards:    pickLowCards(uint16(lowMaskLimit-int(strength)), cards),
+		Strength: strength,
+	}, nil
+}
+
+// bestOmahaLow 枚举两张底牌，与剩余公共牌中最小的三个不同低牌点数组合，返回最好的低牌位集合和所用的两张底牌
+func bestOmahaLow(hole, board []Card) (uint16, int, int) {
+	var boardMask uint16
+	for _, card := range board {
+		boardMask |= lowRankBit(card.Rank)
+	}
+
+	var best uint16
+	bestA, bestB := -1, -1
+	for a := 0; a < len(hole); a++ {
+		bitA := lowRankBit(hole[a].Rank)
+		if bitA == 0 {
+			continue
+		}
+		for b := a + 1; b < len(hole); b++ {
+			bitB := lowRankBit(hole[b].Rank)
+			if bitB == 0 || bitB == bitA {
+				continue
+			}
+			rest := boardMask &^ (bitA | bitB)
+			if bits.OnesCount16(rest) < 3 {
+				continue
+			}
+			if mask := bitA | bitB | lowestBits(rest, 3); best == 0 || mask < best {
+				best, bestA, bestB = mask, a, b
+			}
+		}
+	}
+	return best, bestA, bestB
+}
+
+// OmahaLowStrength 按奥马哈规则（两张底牌加三张公共牌）计算最好的低牌（不分配内存，调用方需保证牌有效且不重复）
+func OmahaLowStrength(hole, board []Card) LowStrength {
+	mask, _, _ := bestOmahaLow(hole, board)
+	return lowStrength(mask)
+}
+
+// EvaluateOmahaLow 按奥马哈规则评估4张底牌和3~5张公共牌的最好低牌（没有合格低牌时Strength为0）
+func EvaluateOmahaLow(hole, board []Card) (LowHand, error) {
+	if len(hole) != OmahaHoleCards {
+		return LowHand{}, fmt.Errorf("奥马哈底牌必须是%d张，实际为%d张", OmahaHoleCards, len(hole))
+	}
+	if len(board) < OmahaMinBoardCards || len(board) > OmahaMaxBoardCards {
+		return LowHand{}, fmt.Errorf("公共牌必须在%d到%d张之间，实际为%d张", OmahaMinBoardCards, OmahaMaxBoardCards, len(board))
+	}
+	if err := validateCards(append(append([]Card{}, hole...), board...)); err != nil {
+		return LowHand{}, err
+	}
+
+	mask, a, b := bestOmahaLow(hole, board)
+	if mask == 0 {
+		return LowHand{}, nil
+	}
+	return LowHand{
+		Cards:    pickLowCards(mask, []Card{hole[a], hole[b]}, board),
+		Strength: lowStrength(mask),
+	}, nil
+}
+
+// SplitHiLo 按高低牌规则拆分底池，返回每位高牌赢家和低牌赢家分得的筹码
+// 没有合格低牌时（lowWinners为0）高牌赢家独得整个底池；否则底池分为两半，无法平分的一个筹码归高牌一半。
+// 每一半由该半的赢家平分，零头按赢家顺序逐个分配，因此赢家应按从庄家左手开始的座位顺序排列。
+// 同一玩家同时赢得高牌和低牌（通吃，或与他人平分其中一半而得到四分之一）时由调用方合并两部分
+func SplitHiLo(amount, highWinners, lowWinners int) (high, low []int) {
+	if lowWinners == 0 {
+		return splitEvenly(amount, highWinners), nil
+	}
+	lowHalf := amount / 2
+	return splitEvenly(amount-lowHalf, highWinners), splitEvenly(lowHalf, lowWinners)
+}
+
+// splitEvenly 将筹码平分给n位赢家，零头依次分给排在前面的赢家
+func splitEvenly(amount, n int) []int {
+	if n <= 0 {
+		return nil
+	}
+
+	shares := make([]int, n)
+	for i := range shares {
+		shares[i] = amount / n
+		if i < amount%n {
+			shares[i]++
+		}
+	}
+	return shares
+}
@@ -0,0 +1,226 @@
+// 查表手牌评估器
+// 作用：基于预计算的点数位掩码查找表计算5~7张牌的最佳牌力，评估过程不分配内存
+
+package poker
+
+import (
+	"math/bits"
+)
+
+// 查找表以13位点数掩码为索引（第0位为2，第12位为A）
+const rankMaskSize = 1 << 13
+
+var (
+	straightTable [rankMaskSize]Rank         // 掩码中最大顺子的最高牌，没有顺子时为0
+	flushTable    [rankMaskSize]HandStrength // 同花掩码（至少5张）对应的同花/同花顺强度
+	uniqueTable   [rankMaskSize]HandStrength // 无对子掩码（至少5张）对应的顺子/高牌强度
+)
+
+func init() {
+	for mask := 0; mask < rankMaskSize; mask++ {
+		straightTable[mask] = findStraight(uint16(mask))
+	}
+
+	for mask := 0; mask < rankMaskSize; mask++ {
+		if bits.OnesCount16(uint16(mask)) < 5 {
+			continue
+		}
+
+		high := straightTable[mask]
+		switch {
+		case high == Ace:
+			flushTable[mask] = HandStrength(RoyalFlush)<<20 | HandStrength(Ace)<<16
+		case high > 0:
+			flushTable[mask] = HandStrength(StraightFlush)<<20 | HandStrength(high)<<16
+		default:
+			flushTable[mask] = HandStrength(Flush)<<20 | packTop(uint16(mask), 5, 0)
+		}
+
+		if high > 0 {
+			uniqueTable[mask] = HandStrength(Straight)<<20 | HandStrength(high)<<16
+		} else {
+			uniqueTable[mask] = HandStrength(HighCard)<<20 | packTop(uint16(mask), 5, 0)
+		}
+	}
+}
+
+// findStraight 查找掩码中最大的顺子（A-2-3-4-5的最高牌为5）
+func findStraight(mask uint16) Rank {
+	const five = 0x1F
+	for low := 8; low >= 0; low-- {
+		if mask>>low&five == five {
+			return Rank(low + 4 + int(Two))
+		}
+	}
+
+	// A-2-3-4-5
+	const wheel = 1<<12 | 0xF
+	if mask&wheel == wheel {
+		return Five
+	}
+	return 0
+}
+
+// rankBit 点数对应的掩码位
+func rankBit(rank Rank) uint16 {
+	return 1 << (rank - Two)
+}
+
+// packTop 将掩码中最大的n个点数依次写入从第slot个位置开始的关键点数位
+func packTop(mask uint16, n, slot int) HandStrength {
+	var strength HandStrength
+	for i := 0; i < n && mask != 0; i++ {
+		top := bits.Len16(mask) - 1
+		mask &^= 1 << top
+		strength |= HandStrength(top+int(Two)) << (16 - 4*(slot+i))
+	}
+	return strength
+}
+
+// Evaluate 计算5~7张牌中最佳五张牌组合的强度（不分配内存）
+// 调用方需保证牌的张数在5到7之间且没有重复
+func Evaluate(cards []Card) HandStrength {
+	var suitMasks [4]uint16
+	var counts [Ace + 1]uint8
+	var rankMask uint16
+	paired := false
+
+	for _, card := range cards {
+		bit := rankBit(card.Rank)
+		suitMasks[card.Suit&3] |= bit
+		if rankMask&bit != 0 {
+			paired = true
+		}
+		rankMask |= bit
+		counts[card.Rank]++
+	}
+
+	// 最多7张牌时，同花不可能与四条或葫芦同时出现
+	for _, mask := range suitMasks {
+		if bits.OnesCount16(mask) >= 5 {
+			return flushTable[mask]
+		}
+	}
+
+	if !paired {
+		return uniqueTable[rankMask]
+	}
+
+	// 按出现次数找出最大的四条、三条和对子
+	var quad, trip, secondTrip, pair, secondPair Rank
+	for rank := Ace; rank >= Two; rank-- {
+		switch counts[rank] {
+		case 4:
+			quad = rank
+		case 3:
+			if trip == 0 {
+				trip = rank
+			} else if secondTrip == 0 {
+				secondTrip = rank
+			}
+		case 2:
+			if pair == 0 {
+				pair = rank
+			} else if secondPair == 0 {
+				secondPair = rank
+			}
+		}
+	}
+
+	switch {
+	case quad > 0:
+		return HandStrength(FourOfAKind)<<20 | HandStrength(quad)<<16 |
+			packTop(rankMask&^rankBit(quad), 1, 1)
+	case trip > 0 && (secondTrip > 0 || pair > 0):
+		return HandStrength(FullHouse)<<20 | HandStrength(trip)<<16 |
+			HandStrength(max(secondTrip, pair))<<12
+	}
+
+	if high := straightTable[rankMask]; high > 0 {
+		return HandStrength(Straight)<<20 | HandStrength(high)<<16
+	}
+
+	switch {
+	case trip > 0:
+		return HandStrength(ThreeOfAKind)<<20 | HandStrength(trip)<<16 |
+			packTop(rankMask&^rankBit(trip), 2, 1)
+	case secondPair > 0:
+		return HandStrength(TwoPair)<<20 | HandStrength(pair)<<16 | HandStrength(secondPair)<<12 |
+			packTop(rankMask&^rankBit(pair)&^rankBit(secondPair), 1, 2)
+	default:
+		return HandStrength(OnePair)<<20 | HandStrength(pair)<<16 |
+			packTop(rankMask&^rankBit(pair), 3, 1)
+	}
+}
+
+// bestFiveCards 根据强度从牌中选出组成该牌力的五张牌（按牌型中的重要性排列）
+func bestFiveCards(cards []Card, strength HandStrength) []Card {
+	handType := strength.Type()
+	ranks := strength.Ranks()
+	best := make([]Card, 0, 5)
+	used := make([]bool, len(cards))
+
+	// take 取出n张指定点数（和花色）的牌
+	take := func(rank Rank, suit Suit, anySuit bool, n int) {
+		for i, card := range cards {
+			if n == 0 {
+				return
+			}
+			if !used[i] && card.Rank == rank && (anySuit || card.Suit == suit) {
+				used[i] = true
+				best = append(best, card)
+				n--
+			}
+		}
+	}
+
+	// 同花类牌型只在同花花色中选牌
+	var flushSuit Suit
+	isFlush := handType == Flush || handType == StraightFlush || handType == RoyalFlush
+	if isFlush {
+		var suitCounts [4]int
+		for _, card := range cards {
+			suitCounts[card.Suit&3]++
+			if suitCounts[card.Suit&3] >= 5 {
+				flushSuit = card.Suit
+			}
+		}
+	}
+
+	switch handType {
+	case Straight, StraightFlush, RoyalFlush:
+		high := ranks[0]
+		for i := 0; i < 5; i++ {
+			rank := high - Rank(i)
+			if rank < Two || strength.ShortDeck() && rank < Six {
+				rank = Ace // A-2-3-4-5（短牌为A-6-7-8-9）中的A
+			}
+			take(rank, flushSuit, !isFlush, 1)
+		}
+	case FourOfAKind:
+		take(ranks[0], 0, true, 4)
+		take(ranks[1], 0, true, 1)
+	case FullHouse:
+		take(ranks[0], 0, true, 3)
+		take(ranks[1], 0, true, 2)
+	case ThreeOfAKind:
+		take(ranks[0], 0, true, 3)
+		take(ranks[1], 0, true, 1)
+		take(ranks[2], 0, true, 1)
+	case TwoPair:
+		take(ranks[0], 0, true, 2)
+		take(ranks[1], 0, true, 2)
+		take(ranks[2], 0, true, 1)
+	case OnePair:
+		take(ranks[0], 0, true, 2)
+		for _, rank := range ranks[1:] {
+			take(rank, 0, true, 1)
+		}
+	default:
+		for _, rank := range ranks {
+			take(rank, flushSuit, !isFlush, 1)
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,144 @@
+// 牌面记法
+// 作用：宽松解析常见的牌面写法（"As"、"AS"、"10h"、"A♠"，以及 "AsKd7c" 这样的整串牌），
+// 并将扑克牌编码为紧凑的字符串JSON（如 "As"），解码时兼容旧的 {"suit":0,"rank":14} 格式
+
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Letter 花色的字母记法（s、h、d、c）
+func (s Suit) Letter() string {
+	switch s {
+	case Spades:
+		return "s"
+	case Hearts:
+		return "h"
+	case Diamonds:
+		return "d"
+	case Clubs:
+		return "c"
+	default:
+		return "?"
+	}
+}
+
+// Notation 扑克牌的紧凑记法（点数加小写花色字母，如 "As"、"Td"）
+func (c Card) Notation() string {
+	return c.Rank.String() + c.Suit.Letter()
+}
+
+// MarshalJSON 将扑克牌编码为紧凑记法字符串
+func (c Card) MarshalJSON() ([]byte, error) {
+	if !c.IsValid() {
+		return nil, fmt.Errorf("无效的扑克牌: suit=%d rank=%d", c.Suit, c.Rank)
+	}
+	return json.Marshal(c.Notation())
+}
+
+// UnmarshalJSON 解码扑克牌，支持记法字符串（宽松解析）和旧的 {"suit","rank"} 对象
+func (c *Card) UnmarshalJSON(data []byte) error {
+	var notation string
+	if err := json.Unmarshal(data, &notation); err == nil {
+		card, err := ParseCard(notation)
+		if err != nil {
+			return err
+		}
+		*c = card
+		return nil
+	}
+
+	var legacy struct {
+		Suit Suit `json:"suit"`
+		Rank Rank `json:"rank"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return fmt.Errorf("无法解析扑克牌: %s", data)
+	}
+	*c = NewCard(legacy.Rank, legacy.Suit)
+	return nil
+}
+
+// String 牌组转紧凑记法字符串（如 "AsKd7c"）
+func (c Cards) String() string {
+	var b strings.Builder
+	for _, card := range c {
+		b.WriteString(card.Notation())
+	}
+	return b.String()
+}
+
+// ParseCard 宽松解析一张牌：点数不区分大小写，10可写作 "T" 或 "10"，花色可用字母（s/h/d/c，不区分大小写）或符号（♠♥♦♣、♤♡♢♧）
+func ParseCard(s string) (Card, error) {
+	cards, err := ParseCards(s)
+	if err != nil {
+		return Card{}, err
+	}
+	if len(cards) != 1 {
+		return Card{}, fmt.Errorf("应为一张牌，实际为%d张: %q", len(cards), s)
+	}
+	return cards[0], nil
+}
+
+// ParseCards 解析由多张牌组成的字符串（如 "AsKd7c"、"As Kd 7c"、"10h,9h"），牌之间可以用空白或逗号分隔
+func ParseCards(s string) (Cards, error) {
+	var cards Cards
+	rest := s
+	for {
+		rest = strings.TrimLeft(rest, " \t\r\n,")
+		if rest == "" {
+			break
+		}
+
+		rank, n := parseRank(rest)
+		if n == 0 {
+			return nil, fmt.Errorf("无效的点数: %q", s)
+		}
+		rest = rest[n:]
+
+		suit, n := parseSuit(rest)
+		if n == 0 {
+			return nil, fmt.Errorf("无效的花色: %q", s)
+		}
+		rest = rest[n:]
+
+		cards = append(cards, NewCard(rank, suit))
+	}
+
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("没有可解析的牌: %q", s)
+	}
+	return cards, nil
+}
+
+// parseRank 解析开头的点数，返回点数和占用的字节数（无法解析时为0）
+func parseRank(s string) (Rank, int) {
+	if strings.HasPrefix(s, "10") {
+		return Ten, 2
+	}
+	if i := strings.IndexByte(rankChars, upperByte(s[0])); i >= 0 {
+		return Rank(i) + Two, 1
+	}
+	return 0, 0
+}
+
+// parseSuit 解析开头的花色，返回花色和占用的字节数（无法解析时为0）
+func parseSuit(s string) (Suit, int) {
+	r, n := utf8.DecodeRuneInString(s)
+	switch r {
+	case 's', 'S', '♠', '♤':
+		return Spades, n
+	case 'h', 'H', '♥', '♡':
+		return Hearts, n
+	case 'd', 'D', '♦', '♢':
+		return Diamonds, n
+	case 'c', 'C', '♣', '♧':
+		return Clubs, n
+	default:
+		return 0, 0
+	}
+}
@@ -0,0 +1,124 @@
+// 牌面记法测试
+// 作用：校验宽松解析的各种牌面写法、整串牌的解析，以及扑克牌JSON的紧凑编码和对旧格式的兼容
+
+package poker
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseCard(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Card
+	}{
+		{input: "As", want: NewCard(Ace, Spades)},
+		{input: "AS", want: NewCard(Ace, Spades)},
+		{input: "as", want: NewCard(Ace, Spades)},
+		{input: "Th", want: NewCard(Ten, Hearts)},
+		{input: "10h", want: NewCard(Ten, Hearts)},
+		{input: "10H", want: NewCard(Ten, Hearts)},
+		{input: "tD", want: NewCard(Ten, Diamonds)},
+		{input: "2c", want: NewCard(Two, Clubs)},
+		{input: "A♠", want: NewCard(Ace, Spades)},
+		{input: "K♥", want: NewCard(King, Hearts)},
+		{input: "Q♢", want: NewCard(Queen, Diamonds)},
+		{input: "j♧", want: NewCard(Jack, Clubs)},
+		{input: " 9d ", want: NewCard(Nine, Diamonds)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCard(tt.input)
+			if err != nil {
+				t.Fatalf("ParseCard: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCard(%q) = %s，期望 %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCardInvalid(t *testing.T) {
+	for _, input := range []string{"", "A", "s", "1s", "11h", "Ax", "AsKd", "A s", "♠A", "10"} {
+		t.Run(input, func(t *testing.T) {
+			if card, err := ParseCard(input); err == nil {
+				t.Errorf("ParseCard(%q) = %s，期望返回错误", input, card)
+			}
+		})
+	}
+}
+
+func TestParseCards(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // 紧凑记法
+	}{
+		{input: "AsKd7c", want: "AsKd7c"},
+		{input: "As Kd 7c", want: "AsKd7c"},
+		{input: "10h,9h", want: "Th9h"},
+		{input: "10h10d", want: "ThTd"},
+		{input: "A♠ K♦, 7♣", want: "AsKd7c"},
+		{input: "\tqsJH\n", want: "QsJh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			cards, err := ParseCards(tt.input)
+			if err != nil {
+				t.Fatalf("ParseCards: %v", err)
+			}
+			if got := cards.String(); got != tt.want {
+				t.Errorf("ParseCards(%q) = %s，期望 %s", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", " , ", "AsK", "AsKx", "As-Kd"} {
+		if cards, err := ParseCards(input); err == nil {
+			t.Errorf("ParseCards(%q) = %v，期望返回错误", input, cards)
+		}
+	}
+}
+
+func TestCardJSON(t *testing.T) {
+	cards := []Card{NewCard(Ace, Spades), NewCard(Ten, Diamonds), NewCard(Two, Clubs)}
+	data, err := json.Marshal(cards)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if got, want := string(data), `["As","Td","2c"]`; got != want {
+		t.Errorf("Marshal = %s，期望 %s", got, want)
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "紧凑记法", data: `["As","Td","2c"]`},
+		{name: "宽松记法", data: `["A♠","10d","2C"]`},
+		{name: "旧的对象格式", data: `[{"suit":0,"rank":14},{"suit":2,"rank":10},{"suit":3,"rank":2}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Card
+			if err := json.Unmarshal([]byte(tt.data), &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, cards) {
+				t.Errorf("Unmarshal(%s) = %v，期望 %v", tt.data, got, cards)
+			}
+		})
+	}
+
+	var card Card
+	if err := json.Unmarshal([]byte(`"Zz"`), &card); err == nil {
+		t.Errorf("无效的记法期望返回错误")
+	}
+	if _, err := json.Marshal(Card{}); err == nil {
+		t.Errorf("无效的扑克牌编码时期望返回错误")
+	}
+}
@@ -0,0 +1,66 @@
+// 奥马哈牌型评估
+// 作用：按奥马哈规则评估牌力，最佳五张牌必须恰好使用两张底牌和三张公共牌
+
+package poker
+
+import (
+	"fmt"
+)
+
+// 奥马哈的底牌和公共牌张数
+const (
+	OmahaHoleCards     = 4
+	OmahaMinBoardCards = 3
+	OmahaMaxBoardCards = 5
+)
+
+// OmahaStrength 计算两张底牌加三张公共牌的最佳牌力（不分配内存，调用方需保证牌有效且不重复）
+func OmahaStrength(hole, board []Card) HandStrength {
+	best, _ := bestOmahaCombo(hole, board)
+	return best
+}
+
+// bestOmahaCombo 枚举所有两张底牌与三张公共牌的组合，返回最大的牌力和对应的五张牌
+func bestOmahaCombo(hole, board []Card) (HandStrength, [5]Card) {
+	var best HandStrength
+	var bestCards, five [5]Card
+	found := false
+
+	for a := 0; a < len(hole); a++ {
+		for b := a + 1; b < len(hole); b++ {
+			five[0], five[1] = hole[a], hole[b]
+			for x := 0; x < len(board); x++ {
+				for y := x + 1; y < len(board); y++ {
+					for z := y + 1; z < len(board); z++ {
+						five[2], five[3], five[4] = board[x], board[y], board[z]
+						if strength := Evaluate(five[:]); !found || strength > best {
+							best, bestCards, found = strength, five, true
+						}
+					}
+				}
+			}
+		}
+	}
+	return best, bestCards
+}
+
+// EvaluateOmaha 按奥马哈规则评估4张底牌和3~5张公共牌的最佳牌型
+func EvaluateOmaha(hole, board []Card) (Hand, error) {
+	if len(hole) != OmahaHoleCards {
+		return Hand{}, fmt.Errorf("奥马哈底牌必须是%d张，实际为%d张", OmahaHoleCards, len(hole))
+	}
+	if len(board) < OmahaMinBoardCards || len(board) > OmahaMaxBoardCards {
+		return Hand{}, fmt.Errorf("公共牌必须在%d到%d张之间，实际为%d张", OmahaMinBoardCards, OmahaMaxBoardCards, len(board))
+	}
+	if err := validateCards(append(append([]Card{}, hole...), board...)); err != nil {
+		return Hand{}, err
+	}
+
+	strength, five := bestOmahaCombo(hole, board)
+	return Hand{
+		Cards:    bestFiveCards(five[:], strength),
+		Type:     strength.Type(),
+		Ranks:    strength.Ranks(),
+		Strength: strength,
+	}, nil
+}
@@ -0,0 +1,101 @@
+// 奥马哈牌型评估测试
+// 作用：校验最佳五张牌恰好使用两张底牌和三张公共牌，以及底牌和公共牌张数的检查
+
+package poker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateOmaha(t *testing.T) {
+	tests := []struct {
+		name      string
+		hole      string
+		board     string
+		wantType  HandType
+		wantRanks []Rank
+	}{
+		{
+			name:     "两张底牌加三张公共牌组成皇家同花顺",
+			hole:     "AsKs2d3c",
+			board:    "QsJsTs9h8h",
+			wantType: RoyalFlush, wantRanks: []Rank{Ace},
+		},
+		{
+			name:     "公共牌的同花顺只能用三张",
+			hole:     "As2d3c4h",
+			board:    "KsQsJsTs9s",
+			wantType: HighCard, wantRanks: []Rank{Ace, King, Queen, Jack, Four},
+		},
+		{
+			name:     "底牌的三条A只能用两张",
+			hole:     "AhAdAcKd",
+			board:    "2s7h9cJsQs",
+			wantType: OnePair, wantRanks: []Rank{Ace, Queen, Jack, Nine},
+		},
+		{
+			name:     "底牌一对加公共牌一对组成四条，踢脚只能来自公共牌",
+			hole:     "9h9d8c7c",
+			board:    "9s9c2h3d4s",
+			wantType: FourOfAKind, wantRanks: []Rank{Nine, Four},
+		},
+		{
+			name:     "底牌只有一张同花时不能成同花",
+			hole:     "AhKs2d3c",
+			board:    "QhJhTh9h",
+			wantType: Straight, wantRanks: []Rank{Ace},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hole, board := parseTestCards(t, tt.hole), parseTestCards(t, tt.board)
+
+			hand, err := EvaluateOmaha(hole, board)
+			if err != nil {
+				t.Fatalf("EvaluateOmaha: %v", err)
+			}
+			if hand.Type != tt.wantType || !reflect.DeepEqual(hand.Ranks, tt.wantRanks) {
+				t.Errorf("牌型 = %s %v，期望 %s %v", hand.Type, hand.Ranks, tt.wantType, tt.wantRanks)
+			}
+			if got := OmahaStrength(hole, board); got != hand.Strength {
+				t.Errorf("OmahaStrength = %v，与 EvaluateOmaha 的 %v 不一致", got, hand.Strength)
+			}
+
+			fromHole := 0
+			for _, card := range hand.Cards {
+				for _, h := range hole {
+					if card == h {
+						fromHole++
+					}
+				}
+			}
+			if len(hand.Cards) != 5 || fromHole != 2 {
+				t.Errorf("最佳五张牌 %v 使用了%d张底牌，期望恰好2张", hand.Cards, fromHole)
+			}
+		})
+	}
+}
+
+func TestEvaluateOmahaInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		hole  string
+		board string
+	}{
+		{name: "底牌只有两张", hole: "AsKs", board: "QsJsTs"},
+		{name: "底牌五张", hole: "AsKsQdJdTc", board: "2c3c4c"},
+		{name: "公共牌不足三张", hole: "AsKs2d3c", board: "QsJs"},
+		{name: "重复的牌", hole: "AsKs2d3c", board: "AsJsTs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hole, board := parseTestCards(t, tt.hole), parseTestCards(t, tt.board)
+			if _, err := EvaluateOmaha(hole, board); err == nil {
+				t.Errorf("期望返回错误")
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+// 手牌范围
+// 作用：解析常用的范围写法（如 "AKs, TT+, A5s-A2s, KQo, 76s+"），展开为带权重的具体底牌组合，并支持移除已知牌
+
+package poker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rankChars 范围写法中的点数字符（按点数从小到大）
+const rankChars = "23456789TJQKA"
+
+// Combo 一手具体的两张底牌及其在范围中的权重（0~1）
+type Combo struct {
+	Cards  [2]Card `json:"cards"`
+	Weight float64 `json:"weight"`
+}
+
+// Contains 判断组合是否包含指定的牌
+func (c Combo) Contains(card Card) bool {
+	return c.Cards[0] == card || c.Cards[1] == card
+}
+
+// Conflicts 判断两个组合是否有相同的牌
+func (c Combo) Conflicts(other Combo) bool {
+	return c.Contains(other.Cards[0]) || c.Contains(other.Cards[1])
+}
+
+// String 组合转字符串
+func (c Combo) String() string {
+	return c.Cards[0].String() + c.Cards[1].String()
+}
+
+// Range 手牌范围（展开后的具体组合，每种组合只出现一次）
+type Range []Combo
+
+// NewCombo 创建权重为1的组合，点数大的牌在前
+func NewCombo(first, second Card) Combo {
+	if second.Rank > first.Rank || (second.Rank == first.Rank && second.Suit < first.Suit) {
+		first, second = second, first
+	}
+	return Combo{Cards: [2]Card{first, second}, Weight: 1}
+}
+
+// Remove 移除包含任意一张已知牌的组合（牌的移除效应）
+func (r Range) Remove(dead ...Card) Range {
+	result := make(Range, 0, len(r))
+	for _, combo := range r {
+		blocked := false
+		for _, card := range dead {
+			if combo.Contains(card) {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			result = append(result, combo)
+		}
+	}
+	return result
+}
+
+// Weight 范围中所有组合的权重之和
+func (r Range) Weight() float64 {
+	total := 0.0
+	for _, combo := range r {
+		total += combo.Weight
+	}
+	return total
+}
+
+// String 范围转字符串（逐个列出组合）
+func (r Range) String() string {
+	parts := make([]string, len(r))
+	for i, combo := range r {
+		parts[i] = combo.String()
+		if combo.Weight != 1 {
+			parts[i] += ":" + strconv.FormatFloat(combo.Weight, 'g', -1, 64)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// handClass 一类起手牌（如 AKs、TT、KQo），kind为'p'（对子）、's'（同花）、'o'（非同花）或0（两者都有）
+type handClass struct {
+	high, low Rank
+	kind      byte
+}
+
+// ParseRange 解析范围写法，多个部分用逗号分隔：
+//
+//	TT、AKs、KQo、AK    对子、同花、非同花、同花加非同花
+//	TT+、A2s+           对子从TT到AA；非连张时踢脚升到比高牌小一级（A2s到AKs）
+//	76s+                连张时两张牌同时升级（76s、87s……AKs），KQs+即KQs和AKs，不包含AQs
+//	A5s-A2s、TT-77      同一高牌的踢脚区间、对子区间
+//	AsKd                具体组合
+//	AKs:0.5             权重（0~1），默认为1
+//
+// 同一组合出现多次时以最后一次的权重为准
+func ParseRange(notation string) (Range, error) {
+	var result Range
+	index := make(map[[2]Card]int)
+
+	for _, part := range strings.Split(notation, ",") {
+		token := strings.TrimSpace(part)
+		if token == "" {
+			continue
+		}
+
+		weight := 1.0
+		if i := strings.IndexByte(token, ':'); i >= 0 {
+			value, err := strconv.ParseFloat(strings.TrimSpace(token[i+1:]), 64)
+			if err != nil || value <= 0 || value > 1 {
+				return nil, fmt.Errorf("无效的权重: %s", token)
+			}
+			weight = value
+			token = strings.TrimSpace(token[:i])
+		}
+
+		combos, err := expandToken(token)
+		if err != nil {
+			return nil, err
+		}
+		for _, combo := range combos {
+			combo.Weight = weight
+			if i, exists := index[combo.Cards]; exists {
+				result[i].Weight = weight
+				continue
+			}
+			index[combo.Cards] = len(result)
+			result = append(result, combo)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("范围为空: %q", notation)
+	}
+	return result, nil
+}
+
+// expandToken 展开范围中的一个部分（不含权重）
+func expandToken(token string) ([]Combo, error) {
+	// 具体组合（如 AsKd）
+	if cards, err := ParseCards(token); err == nil && len(cards) == 2 {
+		if cards[0] == cards[1] {
+			return nil, fmt.Errorf("组合中有重复的牌: %s", token)
+		}
+		return []Combo{NewCombo(cards[0], cards[1])}, nil
+	}
+
+	var classes []handClass
+	switch {
+	case strings.Contains(token, "-"):
+		bounds := strings.SplitN(token, "-", 2)
+		from, err := parseHandClass(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseHandClass(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		if classes, err = classSpan(from, to); err != nil {
+			return nil, fmt.Errorf("无效的范围区间 %s: %v", token, err)
+		}
+	case strings.HasSuffix(token, "+"):
+		base, err := parseHandClass(strings.TrimSuffix(token, "+"))
+		if err != nil {
+			return nil, err
+		}
+		classes = classPlus(base)
+	default:
+		class, err := parseHandClass(token)
+		if err != nil {
+			return nil, err
+		}
+		classes = []handClass{class}
+	}
+
+	var combos []Combo
+	for _, class := range classes {
+		combos = append(combos, class.combos()...)
+	}
+	return combos, nil
+}
+
+// parseHandClass 解析一类起手牌（如 AK、AKs、KQo、TT）
+func parseHandClass(s string) (handClass, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || len(s) > 3 {
+		return handClass{}, fmt.Errorf("无效的起手牌: %q", s)
+	}
+
+	first := strings.IndexByte(rankChars, upperByte(s[0]))
+	second := strings.IndexByte(rankChars, upperByte(s[1]))
+	if first < 0 || second < 0 {
+		return handClass{}, fmt.Errorf("无效的起手牌点数: %q", s)
+	}
+
+	class := handClass{high: Rank(first) + Two, low: Rank(second) + Two}
+	if class.low > class.high {
+		class.high, class.low = class.low, class.high
+	}
+
+	if len(s) == 3 {
+		switch s[2] {
+		case 's', 'S':
+			class.kind = 's'
+		case 'o', 'O':
+			class.kind = 'o'
+		default:
+			return handClass{}, fmt.Errorf("无效的同花标记: %q", s)
+		}
+	}
+
+	if class.high == class.low {
+		if class.kind != 0 {
+			return handClass{}, fmt.Errorf("对子不能指定同花或非同花: %q", s)
+		}
+		class.kind = 'p'
+	}
+	return class, nil
+}
+
+// classPlus 展开"+"写法：对子升到AA；连张两张牌同时升级直到A，与常见范围工具一致（如KQs+为KQs、AKs，JTo+为JTo到AKo）；
+// 其他牌型高牌不变，踢脚升到比高牌小一级（如A9s+为A9s到AKs，K9o+为K9o到KQo）
+func classPlus(base handClass) []handClass {
+	var classes []handClass
+	switch {
+	case base.kind == 'p':
+		for rank := base.high; rank <= Ace; rank++ {
+			classes = append(classes, handClass{high: rank, low: rank, kind: 'p'})
+		}
+	case base.high-base.low == 1:
+		// 连张：两张牌同时升级
+		for high := base.high; high <= Ace; high++ {
+			classes = append(classes, handClass{high: high, low: high - 1, kind: base.kind})
+		}
+	default:
+		for low := base.low; low < base.high; low++ {
+			classes = append(classes, handClass{high: base.high, low: low, kind: base.kind})
+		}
+	}
+	return classes
+}
+
+// classSpan 展开"-"区间：对子区间、同一高牌的踢脚区间，或间隔相同的连续牌型（如 98s-54s）
+func classSpan(from, to handClass) ([]handClass, error) {
+	if from.kind != to.kind {
+		return nil, fmt.Errorf("两端的牌型不一致")
+	}
+
+	var classes []handClass
+	switch {
+	case from.kind == 'p':
+		for rank := min(from.high, to.high); rank <= max(from.high, to.high); rank++ {
+			classes = append(classes, handClass{high: rank, low: rank, kind: 'p'})
+		}
+	case from.high == to.high:
+		for low := min(from.low, to.low); low <= max(from.low, to.low); low++ {
+			classes = append(classes, handClass{high: from.high, low: low, kind: from.kind})
+		}
+	case from.high-from.low == to.high-to.low:
+		gap := from.high - from.low
+		for high := min(from.high, to.high); high <= max(from.high, to.high); high++ {
+			classes = append(classes, handClass{high: high, low: high - gap, kind: from.kind})
+		}
+	default:
+		return nil, fmt.Errorf("两端既不是同一高牌也不是相同间隔")
+	}
+	return classes, nil
+}
+
+// combos 展开为具体组合（对子6种、同花4种、非同花12种）
+func (c handClass) combos() []Combo {
+	var combos []Combo
+	for firstSuit := Spades; firstSuit <= Clubs; firstSuit++ {
+		for secondSuit := Spades; secondSuit <= Clubs; secondSuit++ {
+			switch c.kind {
+			case 'p':
+				if secondSuit <= firstSuit {
+					continue
+				}
+			case 's':
+				if secondSuit != firstSuit {
+					continue
+				}
+			case 'o':
+				if secondSuit == firstSuit {
+					continue
+				}
+			}
+			combos = append(combos, NewCombo(NewCard(c.high, firstSuit), NewCard(c.low, secondSuit)))
+		}
+	}
+	return combos
+}
+
+// upperByte 将小写字母转为大写
+func upperByte(b byte) byte {
+	if b >= 'a' && b <= 'z' {
+		return b - 'a' + 'A'
+	}
+	return b
+}
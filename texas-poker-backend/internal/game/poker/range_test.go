@@ -0,0 +1,157 @@
+// 手牌范围测试
+// 作用：校验范围写法展开的具体组合和组合数、权重，以及移除已知牌的效果
+
+package poker
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// rangeClasses 范围中的组合按起手牌归类（如 "AKs"、"TT"、"KQo"），返回排好序的类别
+func rangeClasses(r Range) []string {
+	seen := make(map[string]bool)
+	var classes []string
+	for _, combo := range r {
+		high, low := combo.Cards[0], combo.Cards[1]
+		class := high.Rank.String() + low.Rank.String()
+		switch {
+		case high.Rank == low.Rank:
+		case high.Suit == low.Suit:
+			class += "s"
+		default:
+			class += "o"
+		}
+		if !seen[class] {
+			seen[class] = true
+			classes = append(classes, class)
+		}
+	}
+	sort.Strings(classes)
+	return classes
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		notation    string
+		wantClasses []string
+		wantCombos  int
+	}{
+		{notation: "AA", wantClasses: []string{"AA"}, wantCombos: 6},
+		{notation: "AKs", wantClasses: []string{"AKs"}, wantCombos: 4},
+		{notation: "AKo", wantClasses: []string{"AKo"}, wantCombos: 12},
+		{notation: "AK", wantClasses: []string{"AKo", "AKs"}, wantCombos: 16},
+		{notation: "QQ+", wantClasses: []string{"AA", "KK", "QQ"}, wantCombos: 18},
+		{notation: "KQs+", wantClasses: []string{"AKs", "KQs"}, wantCombos: 8},
+		{notation: "JTo+", wantClasses: []string{"AKo", "JTo", "KQo", "QJo"}, wantCombos: 48},
+		{notation: "76s+", wantClasses: []string{"76s", "87s", "98s", "AKs", "JTs", "KQs", "QJs", "T9s"}, wantCombos: 32},
+		{notation: "A9s+", wantClasses: []string{"A9s", "AJs", "AKs", "AQs", "ATs"}, wantCombos: 20},
+		{notation: "K9o+", wantClasses: []string{"K9o", "KJo", "KQo", "KTo"}, wantCombos: 48},
+		{notation: "A5s-A2s", wantClasses: []string{"A2s", "A3s", "A4s", "A5s"}, wantCombos: 16},
+		{notation: "TT-88", wantClasses: []string{"88", "99", "TT"}, wantCombos: 18},
+		{notation: "98s-76s", wantClasses: []string{"76s", "87s", "98s"}, wantCombos: 12},
+		{notation: "AsKd", wantClasses: []string{"AKo"}, wantCombos: 1},
+		{notation: "ak, kk", wantClasses: []string{"AKo", "AKs", "KK"}, wantCombos: 22},
+		{notation: "AKs, AK", wantClasses: []string{"AKo", "AKs"}, wantCombos: 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			r, err := ParseRange(tt.notation)
+			if err != nil {
+				t.Fatalf("ParseRange: %v", err)
+			}
+			if len(r) != tt.wantCombos {
+				t.Errorf("组合数 = %d，期望 %d", len(r), tt.wantCombos)
+			}
+			if got := rangeClasses(r); !reflect.DeepEqual(got, tt.wantClasses) {
+				t.Errorf("起手牌 = %v，期望 %v", got, tt.wantClasses)
+			}
+		})
+	}
+}
+
+func TestParseRangeWeights(t *testing.T) {
+	tests := []struct {
+		notation   string
+		wantCombos int
+		wantWeight float64
+	}{
+		{notation: "AKs:0.5", wantCombos: 4, wantWeight: 2},
+		{notation: "AA, AA:0.25", wantCombos: 6, wantWeight: 1.5},    // 同一组合以最后一次的权重为准
+		{notation: "AK:0.5, AKs", wantCombos: 16, wantWeight: 6 + 4}, // 非同花12×0.5，同花4×1
+		{notation: "AsKs:0.1", wantCombos: 1, wantWeight: 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			r, err := ParseRange(tt.notation)
+			if err != nil {
+				t.Fatalf("ParseRange: %v", err)
+			}
+			if len(r) != tt.wantCombos {
+				t.Errorf("组合数 = %d，期望 %d", len(r), tt.wantCombos)
+			}
+			if got := r.Weight(); got < tt.wantWeight-1e-9 || got > tt.wantWeight+1e-9 {
+				t.Errorf("Weight() = %v，期望 %v", got, tt.wantWeight)
+			}
+		})
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	for _, notation := range []string{
+		"",
+		" , ",
+		"AKx",
+		"AAs",
+		"ZZ",
+		"AKs:0",
+		"AKs:1.5",
+		"AKs:abc",
+		"AsAs",
+		"AKs-KQo",
+		"A5s-K2s",
+	} {
+		t.Run(notation, func(t *testing.T) {
+			if r, err := ParseRange(notation); err == nil {
+				t.Errorf("ParseRange(%q) = %v，期望返回错误", notation, r)
+			}
+		})
+	}
+}
+
+func TestRangeRemove(t *testing.T) {
+	tests := []struct {
+		notation string
+		dead     string
+		want     int
+	}{
+		{notation: "AA", dead: "As", want: 3},
+		{notation: "AA", dead: "AsAh", want: 1},
+		{notation: "AKs", dead: "AsKh", want: 2},
+		{notation: "AK", dead: "Qc", want: 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation+"-"+tt.dead, func(t *testing.T) {
+			r, err := ParseRange(tt.notation)
+			if err != nil {
+				t.Fatalf("ParseRange: %v", err)
+			}
+			dead := parseTestCards(t, tt.dead)
+			remaining := r.Remove(dead...)
+			if len(remaining) != tt.want {
+				t.Errorf("移除后组合数 = %d，期望 %d", len(remaining), tt.want)
+			}
+			for _, combo := range remaining {
+				for _, card := range dead {
+					if combo.Contains(card) {
+						t.Errorf("组合 %s 包含已移除的牌 %s", combo, card)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// 洗牌随机数源
+// 作用：定义牌堆使用的随机数接口，默认使用crypto/rand的无偏随机数，测试和牌局重放时可使用固定种子的确定性随机数
+
+package poker
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"math"
+	"math/rand"
+)
+
+// RNG 洗牌使用的随机数源
+type RNG interface {
+	// Intn 返回[0, n)内均匀分布的随机整数，n必须大于0
+	Intn(n int) int
+}
+
+// cryptoRNG 基于crypto/rand的随机数源，不可预测且可在多个goroutine中共享
+type cryptoRNG struct{}
+
+// NewCryptoRNG 创建基于crypto/rand的无偏随机数源
+func NewCryptoRNG() RNG {
+	return cryptoRNG{}
+}
+
+// Intn 通过拒绝采样消除取模偏差
+func (cryptoRNG) Intn(n int) int {
+	if n <= 0 {
+		panic("随机数范围必须大于0")
+	}
+
+	bound := uint64(n)
+	// 丢弃落在最后一段不完整区间内的值，保证每个结果的概率相同
+	limit := math.MaxUint64 - math.MaxUint64%bound
+	var buf [8]byte
+	for {
+		if _, err := cryptorand.Read(buf[:]); err != nil {
+			panic("读取系统随机数失败: " + err.Error())
+		}
+		if value := binary.LittleEndian.Uint64(buf[:]); value < limit {
+			return int(value % bound)
+		}
+	}
+}
+
+// NewSeededRNG 创建固定种子的确定性随机数源（相同种子产生相同的洗牌结果，不可用于真实牌局）
+func NewSeededRNG(seed int64) RNG {
+	return rand.New(rand.NewSource(seed))
+}
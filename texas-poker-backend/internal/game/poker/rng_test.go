@@ -0,0 +1,94 @@
+// 洗牌随机数源测试
+// 作用：校验固定种子的洗牌结果可重现、洗牌结果是完整的一副牌，以及crypto/rand随机数的范围和均匀性
+
+package poker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeededShuffle(t *testing.T) {
+	tests := []struct {
+		seed int64
+		want string // 洗牌后的前10张牌
+	}{
+		{seed: 42, want: "5sJs8d3sJh3h6cAhJdAc"},
+		{seed: 7, want: "5d4s4h3cTd2d9sJs7d6h"},
+	}
+
+	for _, tt := range tests {
+		for run := 0; run < 2; run++ {
+			deck := NewDeckWithRNG(NewSeededRNG(tt.seed))
+			deck.Shuffle()
+			if got, want := deck.GetAllCards()[:10], parseTestCards(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("种子%d第%d次洗牌的前10张 = %v，期望 %v", tt.seed, run+1, got, want)
+			}
+		}
+	}
+}
+
+func TestShuffleIsPermutation(t *testing.T) {
+	tests := []struct {
+		name string
+		rng  RNG
+	}{
+		{name: "crypto/rand", rng: NewCryptoRNG()},
+		{name: "固定种子", rng: NewSeededRNG(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deck := NewDeckWithRNG(tt.rng)
+			deck.Shuffle()
+
+			seen := make(map[Card]bool)
+			for deck.CanDeal() {
+				card := deck.Deal()
+				if card.Rank < Two || card.Rank > Ace || card.Suit < Spades || card.Suit > Clubs || seen[card] {
+					t.Fatalf("洗牌后出现无效或重复的牌: %s", card)
+				}
+				seen[card] = true
+			}
+			if len(seen) != 52 {
+				t.Errorf("洗牌后共%d张牌，期望 52", len(seen))
+			}
+		})
+	}
+}
+
+func TestCryptoRNGIntn(t *testing.T) {
+	const (
+		buckets = 6
+		draws   = 60000
+	)
+	rng := NewCryptoRNG()
+	counts := make([]int, buckets)
+	for i := 0; i < draws; i++ {
+		n := rng.Intn(buckets)
+		if n < 0 || n >= buckets {
+			t.Fatalf("Intn(%d) = %d 超出范围", buckets, n)
+		}
+		counts[n]++
+	}
+
+	// 每个结果期望10000次，标准差约91，允许约5.5倍标准差的偏差
+	for n, count := range counts {
+		if count < draws/buckets-500 || count > draws/buckets+500 {
+			t.Errorf("结果%d出现%d次，偏离期望的 %d 过多", n, count, draws/buckets)
+		}
+	}
+
+	if got := rng.Intn(1); got != 0 {
+		t.Errorf("Intn(1) = %d，期望 0", got)
+	}
+}
+
+func TestCryptoRNGIntnPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Intn(0) 应当panic")
+		}
+	}()
+	NewCryptoRNG().Intn(0)
+}
@@ -0,0 +1,75 @@
+// 短牌牌型评估
+// 作用：按短牌（6+）规则评估牌力：牌堆去掉2到5，同花大于葫芦，A-6-7-8-9为最小的顺子
+
+package poker
+
+import (
+	"fmt"
+)
+
+// shortWheel A-6-7-8-9在点数掩码中对应的位（第0位为2，第12位为A）
+const shortWheel = 1<<12 | 0xF<<4
+
+// ShortDeckStrength 按短牌规则计算5~7张牌的最佳牌力（不分配内存，调用方需保证牌有效且不重复）
+// 先用标准评估器计算，再补上标准规则不认识的A-6-7-8-9顺子（同花顺），最后互换同花和葫芦的等级
+func ShortDeckStrength(cards []Card) HandStrength {
+	strength := Evaluate(cards)
+
+	if strength.Type() < StraightFlush {
+		var all uint16
+		var suits [4]uint16
+		for _, card := range cards {
+			bit := rankBit(card.Rank)
+			all |= bit
+			suits[card.Suit&3] |= bit
+		}
+
+		// 短牌中其他顺子的最高牌至少为10，因此只有在没有顺子时才需要补上A-6-7-8-9
+		wheelFlush := false
+		for _, mask := range suits {
+			if mask&shortWheel == shortWheel {
+				wheelFlush = true
+				break
+			}
+		}
+		switch {
+		case wheelFlush:
+			strength = HandStrength(StraightFlush)<<20 | HandStrength(Nine)<<16
+		case strength.Type() < Straight && all&shortWheel == shortWheel:
+			strength = HandStrength(Straight)<<20 | HandStrength(Nine)<<16
+		}
+	}
+
+	// 同花与葫芦互换等级，Type()根据短牌标记还原牌型
+	switch strength.Type() {
+	case Flush:
+		strength = strength&^(0xF<<20) | HandStrength(FullHouse)<<20
+	case FullHouse:
+		strength = strength&^(0xF<<20) | HandStrength(Flush)<<20
+	}
+	return strength | shortDeckFlag
+}
+
+// EvaluateShortDeck 按短牌规则评估5~7张牌的最佳5张牌组合（牌中不能有2到5）
+func EvaluateShortDeck(cards []Card) (Hand, error) {
+	if len(cards) < MinHandCards || len(cards) > MaxHandCards {
+		return Hand{}, fmt.Errorf("牌数必须在%d到%d张之间，实际为%d张", MinHandCards, MaxHandCards, len(cards))
+	}
+	if err := validateCards(cards); err != nil {
+		return Hand{}, err
+	}
+	for _, card := range cards {
+		if !ShortDeck.Contains(card) {
+			return Hand{}, fmt.Errorf("短牌中没有%s", card)
+		}
+	}
+
+	strength := ShortDeckStrength(cards)
+
+	return Hand{
+		Cards:    bestFiveCards(cards, strength),
+		Type:     strength.Type(),
+		Ranks:    strength.Ranks(),
+		Strength: strength,
+	}, nil
+}
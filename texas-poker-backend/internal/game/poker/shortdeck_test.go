@@ -0,0 +1,118 @@
+// 短牌牌型评估测试
+// 作用：校验短牌的牌型大小顺序（同花大于葫芦、A-6-7-8-9为最小的顺子）、36张的牌堆以及短牌的公平洗牌结果
+
+package poker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShortDeckRankingOrder(t *testing.T) {
+	// 从大到小排列，每一手都严格大于下一手
+	hands := []struct {
+		notation string
+		wantType HandType
+	}{
+		{notation: "AsKsQsJsTs", wantType: RoyalFlush},
+		{notation: "KhQhJhTh9h", wantType: StraightFlush},
+		{notation: "9s8s7s6sAs", wantType: StraightFlush}, // A-6-7-8-9同花顺是最小的同花顺
+		{notation: "AcAdAhAs6c", wantType: FourOfAKind},
+		{notation: "AhJh9h7h6h", wantType: Flush}, // 同花大于葫芦
+		{notation: "KcKdKh6s6d", wantType: FullHouse},
+		{notation: "AdKcQhJsTd", wantType: Straight},
+		{notation: "Ad6c7h8s9d", wantType: Straight}, // A-6-7-8-9是最小的顺子
+		{notation: "QcQdQhAs9d", wantType: ThreeOfAKind},
+		{notation: "JcJdTcTdAh", wantType: TwoPair},
+		{notation: "7c7dAhKsQd", wantType: OnePair},
+		{notation: "AhKdQc9s8d", wantType: HighCard},
+	}
+
+	var previous HandStrength
+	for i, tt := range hands {
+		hand, err := EvaluateShortDeck(parseTestCards(t, tt.notation))
+		if err != nil {
+			t.Fatalf("EvaluateShortDeck(%q): %v", tt.notation, err)
+		}
+		if hand.Type != tt.wantType {
+			t.Errorf("%s 牌型 = %s，期望 %s", tt.notation, hand.Type, tt.wantType)
+		}
+		if i > 0 && hand.Strength >= previous {
+			t.Errorf("%s（%v）应小于 %s（%v）", tt.notation, hand.Strength, hands[i-1].notation, previous)
+		}
+		previous = hand.Strength
+	}
+}
+
+func TestEvaluateShortDeckBestFive(t *testing.T) {
+	tests := []struct {
+		notation string
+		wantType HandType
+		wantHigh Rank
+	}{
+		{notation: "As6s7s8s9sKdKc", wantType: StraightFlush, wantHigh: Nine},
+		{notation: "Ad6c7h8s9dKdKc", wantType: Straight, wantHigh: Nine},
+		{notation: "AhJh9h7h6hAdAc", wantType: Flush, wantHigh: Ace},      // 同花和三条A都有时同花更大
+		{notation: "KcKdKh6s6dAhQh", wantType: FullHouse, wantHigh: King}, // 没有同花时仍是葫芦
+		{notation: "TdJcQhKsAd6c7h", wantType: Straight, wantHigh: Ace},   // 有更大的顺子时不按A-6-7-8-9计算
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			cards := parseTestCards(t, tt.notation)
+			hand, err := EvaluateShortDeck(cards)
+			if err != nil {
+				t.Fatalf("EvaluateShortDeck: %v", err)
+			}
+			if hand.Type != tt.wantType || hand.Ranks[0] != tt.wantHigh {
+				t.Errorf("牌型 = %s %v，期望 %s 最大点数 %s", hand.Type, hand.Ranks, tt.wantType, tt.wantHigh)
+			}
+			if got := TexasHoldem.Strength(cards[:2], cards[2:]); got.ShortDeck() {
+				t.Errorf("德州扑克的强度不应带有短牌标记")
+			}
+			if got := ShortDeckHoldem.Strength(cards[:2], cards[2:]); got != hand.Strength {
+				t.Errorf("ShortDeckHoldem.Strength = %v，与 EvaluateShortDeck 的 %v 不一致", got, hand.Strength)
+			}
+		})
+	}
+}
+
+func TestEvaluateShortDeckInvalid(t *testing.T) {
+	for _, notation := range []string{"As6s7s8s", "As6s7s8s2s", "As6s7s8s9sTsJsQs", "AsAs7s8s9s"} {
+		if _, err := EvaluateShortDeck(parseTestCards(t, notation)); err == nil {
+			t.Errorf("EvaluateShortDeck(%q) 期望返回错误", notation)
+		}
+	}
+}
+
+func TestShortDeck(t *testing.T) {
+	deck := NewShortDeck()
+	deck.Shuffle()
+	seen := make(map[Card]bool)
+	for deck.CanDeal() {
+		card := deck.Deal()
+		if card.Rank < Six || seen[card] {
+			t.Fatalf("短牌中出现无效或重复的牌: %s", card)
+		}
+		seen[card] = true
+	}
+	if len(seen) != 36 || ShortDeck.Size() != 36 {
+		t.Errorf("短牌共%d张（Size() = %d），期望 36", len(seen), ShortDeck.Size())
+	}
+
+	// 与标准牌相同的种子，按短牌的标准顺序推导出不同的牌堆
+	fair := NewFairDeck(ShortDeck, "server-seed", []string{"alice", "bob"})
+	if got, want := fair.GetAllCards()[:10], parseTestCards(t, "QsQdTc9s8cJhJs7cKs6d"); !reflect.DeepEqual(got, want) {
+		t.Errorf("短牌公平牌堆前10张 = %v，期望 %v", got, want)
+	}
+}
+
+func TestShortDeckHandRankings(t *testing.T) {
+	rankings := ShortDeckHoldem.HandRankings()
+	if rankings[3] != Flush || rankings[4] != FullHouse {
+		t.Errorf("短牌的第4、5大牌型 = %s、%s，期望同花、葫芦", rankings[3], rankings[4])
+	}
+	if standard := TexasHoldem.HandRankings(); standard[3] != FullHouse || standard[4] != Flush {
+		t.Errorf("德州扑克的第4、5大牌型 = %s、%s，期望葫芦、同花", standard[3], standard[4])
+	}
+}
@@ -0,0 +1,81 @@
+// 手牌强度
+// 作用：将牌型和关键点数编码为可直接比较的强度值，7462种不同的五张牌牌型等级各对应唯一的值
+
+package poker
+
+import (
+	"fmt"
+)
+
+// HandStrength 手牌强度（数值越大牌力越强）
+// 编码方式：牌型占第20位以上，关键点数按重要性依次占4位（第16~19位为最重要的点数）。
+// 同一牌型内先比较最重要的点数，再依次比较踢脚，因此任意两手牌的强度相等当且仅当牌力完全相同。
+// 短牌的强度带有第24位标记，并互换同花和葫芦的等级，使同花大于葫芦（只应与短牌的强度比较）
+type HandStrength uint32
+
+// shortDeckFlag 短牌强度标记
+const shortDeckFlag HandStrength = 1 << 24
+
+// rankSlots 每种牌型参与比较的关键点数个数
+var rankSlots = map[HandType]int{
+	HighCard:      5,
+	OnePair:       4,
+	TwoPair:       3,
+	ThreeOfAKind:  3,
+	Straight:      1,
+	Flush:         5,
+	FullHouse:     2,
+	FourOfAKind:   2,
+	StraightFlush: 1,
+	RoyalFlush:    1,
+}
+
+// NewHandStrength 根据牌型和按重要性排列的关键点数生成手牌强度
+func NewHandStrength(handType HandType, ranks []Rank) HandStrength {
+	strength := HandStrength(handType) << 20
+	for i := 0; i < len(ranks) && i < 5; i++ {
+		strength |= HandStrength(ranks[i]&0xF) << (16 - 4*i)
+	}
+	return strength
+}
+
+// Type 获取强度对应的牌型
+func (s HandStrength) Type() HandType {
+	handType := HandType(s >> 20 & 0xF)
+	if s.ShortDeck() && (handType == Flush || handType == FullHouse) {
+		return Flush + FullHouse - handType
+	}
+	return handType
+}
+
+// ShortDeck 是否为短牌规则下的强度
+func (s HandStrength) ShortDeck() bool {
+	return s&shortDeckFlag != 0
+}
+
+// Ranks 获取强度中编码的关键点数（按重要性排列）
+func (s HandStrength) Ranks() []Rank {
+	n := rankSlots[s.Type()]
+	ranks := make([]Rank, n)
+	for i := 0; i < n; i++ {
+		ranks[i] = Rank(s>>(16-4*i)) & 0xF
+	}
+	return ranks
+}
+
+// Compare 比较两个强度，返回1表示s更强，-1表示other更强，0表示牌力相同
+func (s HandStrength) Compare(other HandStrength) int {
+	switch {
+	case s > other:
+		return 1
+	case s < other:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// String 强度转字符串（牌型和关键点数）
+func (s HandStrength) String() string {
+	return fmt.Sprintf("%s %v", s.Type().String(), s.Ranks())
+}
@@ -0,0 +1,136 @@
+// 游戏玩法
+// 作用：定义房间可选的扑克玩法（德州扑克、短牌德州扑克、底池限注奥马哈、奥马哈高低牌），并按玩法确定牌堆、底牌张数、下注限制和牌力评估方式
+
+package poker
+
+import (
+	"fmt"
+)
+
+// GameType 游戏玩法
+type GameType string
+
+const (
+	TexasHoldem     GameType = "holdem" // 无限注德州扑克
+	ShortDeckHoldem GameType = "short"  // 无限注短牌德州扑克（6+）
+	PotLimitOmaha   GameType = "plo"    // 底池限注奥马哈
+	OmahaHiLo       GameType = "plo8"   // 底池限注奥马哈高低牌（8或更小）
+)
+
+// GameTypes 所有可选的玩法
+var GameTypes = []GameType{TexasHoldem, ShortDeckHoldem, PotLimitOmaha, OmahaHiLo}
+
+// ParseGameType 解析玩法标识（为空时为德州扑克）
+func ParseGameType(s string) (GameType, error) {
+	if s == "" {
+		return TexasHoldem, nil
+	}
+	for _, gameType := range GameTypes {
+		if string(gameType) == s {
+			return gameType, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的玩法: %s", s)
+}
+
+// String 玩法的中文名称
+func (g GameType) String() string {
+	switch g {
+	case TexasHoldem:
+		return "无限注德州扑克"
+	case ShortDeckHoldem:
+		return "无限注短牌德州扑克"
+	case PotLimitOmaha:
+		return "底池限注奥马哈"
+	case OmahaHiLo:
+		return "底池限注奥马哈高低牌"
+	default:
+		return "未知玩法"
+	}
+}
+
+// DeckType 玩法使用的牌堆
+func (g GameType) DeckType() DeckType {
+	if g == ShortDeckHoldem {
+		return ShortDeck
+	}
+	return StandardDeck
+}
+
+// HandRankings 玩法中的牌型从大到小的排列（短牌中同花大于葫芦）
+func (g GameType) HandRankings() []HandType {
+	rankings := []HandType{RoyalFlush, StraightFlush, FourOfAKind, FullHouse, Flush, Straight, ThreeOfAKind, TwoPair, OnePair, HighCard}
+	if g == ShortDeckHoldem {
+		rankings[3], rankings[4] = Flush, FullHouse
+	}
+	return rankings
+}
+
+// HoleCards 每位玩家的底牌张数
+func (g GameType) HoleCards() int {
+	if g.omaha() {
+		return OmahaHoleCards
+	}
+	return 2
+}
+
+// PotLimit 是否为底池限注
+func (g GameType) PotLimit() bool {
+	return g.omaha()
+}
+
+// HiLo 是否为高低牌玩法（底池由最大的高牌和合格的低牌各得一半）
+func (g GameType) HiLo() bool {
+	return g == OmahaHiLo
+}
+
+// omaha 是否按奥马哈规则（两张底牌加三张公共牌）组成牌型
+func (g GameType) omaha() bool {
+	return g == PotLimitOmaha || g == OmahaHiLo
+}
+
+// Evaluate 按玩法规则评估底牌和公共牌组成的最佳牌型（公共牌至少3张）
+func (g GameType) Evaluate(hole, board []Card) (Hand, error) {
+	if g.omaha() {
+		return EvaluateOmaha(hole, board)
+	}
+
+	cards := make([]Card, 0, len(hole)+len(board))
+	cards = append(cards, hole...)
+	cards = append(cards, board...)
+	if g == ShortDeckHoldem {
+		return EvaluateShortDeck(cards)
+	}
+	return EvaluateHand(cards)
+}
+
+// Strength 按玩法规则计算牌力（不分配内存，调用方需保证牌有效且不重复）
+func (g GameType) Strength(hole, board []Card) HandStrength {
+	if g.omaha() {
+		return OmahaStrength(hole, board)
+	}
+
+	var cards [MaxHandCards]Card
+	n := copy(cards[:], hole)
+	n += copy(cards[n:], board)
+	if g == ShortDeckHoldem {
+		return ShortDeckStrength(cards[:n])
+	}
+	return Evaluate(cards[:n])
+}
+
+// EvaluateLow 按玩法规则评估最好的低牌（非高低牌玩法返回错误，没有合格低牌时Strength为0）
+func (g GameType) EvaluateLow(hole, board []Card) (LowHand, error) {
+	if !g.HiLo() {
+		return LowHand{}, fmt.Errorf("%s不分低牌", g)
+	}
+	return EvaluateOmahaLow(hole, board)
+}
+
+// LowStrength 按玩法规则计算低牌强度（非高低牌玩法返回0，不分配内存）
+func (g GameType) LowStrength(hole, board []Card) LowStrength {
+	if !g.HiLo() {
+		return 0
+	}
+	return OmahaLowStrength(hole, board)
+}
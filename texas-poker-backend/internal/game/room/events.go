@@ -0,0 +1,143 @@
+// 房间事件
+// 作用：记录牌局推进过程中产生的事件（玩家操作、发牌、摊牌、结算等），在释放房间锁后交给处理器推送给客户端
+
+package room
+
+import (
+	"log"
+	"time"
+
+	"texas-poker-backend/internal/game/poker/equity"
+)
+
+// 房间事件类型（同时作为WebSocket消息类型）
+const (
+	EventHandStarted   = "hand_started"   // 新一局开始
+	EventPlayerAction  = "player_action"  // 玩家完成一次操作
+	EventStreetChanged = "street_changed" // 进入新的下注街（翻牌/转牌/河牌）
+	EventAllInRunout   = "all_in_runout"  // 所有玩家全押，自动发完剩余公共牌
+	EventShowdown      = "showdown"       // 摊牌，公开仍在牌局中的玩家底牌
+	EventHandEnded     = "hand_ended"     // 本局结束并完成结算
+)
+
+// 全押推送的胜率只用于展示，计算量比胜率接口小得多，避免拖慢推送事件的goroutine
+// 翻牌后的全押通常可以精确枚举，翻牌前（公共牌组合过多，奥马哈尤甚）用少量模拟估算
+const (
+	liveEquityExactLimit = 200000
+	liveEquityIterations = 2000
+)
+
+// Event 房间事件
+type Event struct {
+	Type   string                 `json:"type"`
+	RoomID int64                  `json:"room_id"`
+	State  string                 `json:"state"` // 事件发生后的游戏阶段
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Time   time.Time              `json:"time"`
+	Hand   *HandRecord            `json:"-"` // 本局完整记录（仅hand_ended事件携带，包含所有底牌，只用于服务端持久化）
+
+	equity *equity.Request // 推送前需要计算胜率的全押底牌（仅all_in_runout事件携带）
+}
+
+// Payload 生成推送给客户端的消息内容：事件数据放在顶层，并附带房间ID、事件发生后的游戏阶段和时间
+func (e Event) Payload() map[string]interface{} {
+	payload := make(map[string]interface{}, len(e.Data)+3)
+	for key, value := range e.Data {
+		payload[key] = value
+	}
+	payload["room_id"] = e.RoomID
+	payload["state"] = e.State
+	payload["time"] = e.Time
+	return payload
+}
+
+// RevealsHoleCards 事件是否公开了底牌（摊牌和全押自动发牌），需要按观察者分别推送
+func (e Event) RevealsHoleCards() bool {
+	return e.Type == EventShowdown || e.Type == EventAllInRunout
+}
+
+// PayloadFor 生成指定观察者收到的消息内容
+// 观战者看不到公开的底牌，也看不到包含底牌的牌型描述，只保留牌型和胜率
+func (e Event) PayloadFor(seated bool) map[string]interface{} {
+	payload := e.Payload()
+	hands, ok := e.Data["hands"].([]map[string]interface{})
+	if seated || !ok {
+		return payload
+	}
+
+	redacted := make([]map[string]interface{}, len(hands))
+	for i, hand := range hands {
+		redacted[i] = make(map[string]interface{}, len(hand))
+		for key, value := range hand {
+			if key != "cards" && key != "description" {
+				redacted[i][key] = value
+			}
+		}
+	}
+	payload["hands"] = redacted
+	return payload
+}
+
+// EventHandler 房间事件处理函数（在房间锁之外调用，可以安全地读取房间状态）
+type EventHandler func(event Event)
+
+// SetEventHandler 设置房间事件处理函数
+func (r *Room) SetEventHandler(handler EventHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.eventHandler = handler
+}
+
+// emit 记录一个待推送的事件（调用方需持有写锁）
+func (r *Room) emit(eventType string, data map[string]interface{}) {
+	r.pendingEvents = append(r.pendingEvents, Event{
+		Type:   eventType,
+		RoomID: r.ID,
+		State:  r.StateMachine.GetCurrentState().Key(),
+		Data:   data,
+		Time:   time.Now(),
+	})
+}
+
+// emitHandEnded 记录一局结束的事件，并附带用于持久化的完整牌局记录（调用方需持有写锁）
+func (r *Room) emitHandEnded(hand *HandRecord, data map[string]interface{}) {
+	r.emit(EventHandEnded, data)
+	r.pendingEvents[len(r.pendingEvents)-1].Hand = hand
+}
+
+// flushEvents 将待推送的事件按发生顺序交给事件处理函数
+// 需要在获取房间锁之前通过defer注册，保证在释放锁之后执行
+func (r *Room) flushEvents() {
+	r.mu.Lock()
+	events := r.pendingEvents
+	r.pendingEvents = nil
+	handler := r.eventHandler
+	r.mu.Unlock()
+
+	if handler == nil {
+		return
+	}
+	for _, event := range events {
+		if event.equity != nil {
+			event.fillEquity()
+		}
+		handler(event)
+	}
+}
+
+// fillEquity 计算全押玩家的胜率并填入事件的各手底牌（在房间锁之外调用）
+func (e *Event) fillEquity() {
+	result, err := equity.Calculate(*e.equity)
+	if err != nil {
+		log.Printf("Failed to calculate all-in equity in room %d: %v", e.RoomID, err)
+		return
+	}
+
+	hands, _ := e.Data["hands"].([]map[string]interface{})
+	for i, hand := range hands {
+		hand["win"] = result.Hands[i].Win
+		hand["tie"] = result.Hands[i].Tie
+		hand["equity"] = result.Hands[i].Equity
+	}
+}
@@ -0,0 +1,127 @@
+// 房间事件测试
+// 作用：校验事件消息内容的结构、摊牌和全押事件对观战者隐藏底牌，以及全押推送的胜率计算量
+
+package room
+
+import (
+	"reflect"
+	"testing"
+
+	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/poker/equity"
+)
+
+func TestEventPayloadFor(t *testing.T) {
+	hands := func() []map[string]interface{} {
+		return []map[string]interface{}{
+			{"player_id": int64(1), "cards": "AsKs", "hand_type": "一对", "description": "desc", "equity": 0.6},
+			{"player_id": int64(2), "cards": "QdQc", "hand_type": "三条", "equity": 0.4},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		event     Event
+		seated    bool
+		wantHands []map[string]interface{} // nil表示消息中不应有hands字段
+	}{
+		{
+			name:      "入座玩家看到摊牌的底牌",
+			event:     Event{Type: EventShowdown, Data: map[string]interface{}{"hands": hands()}},
+			seated:    true,
+			wantHands: hands(),
+		},
+		{
+			name:   "观战者看不到摊牌的底牌和牌型描述",
+			event:  Event{Type: EventShowdown, Data: map[string]interface{}{"hands": hands()}},
+			seated: false,
+			wantHands: []map[string]interface{}{
+				{"player_id": int64(1), "hand_type": "一对", "equity": 0.6},
+				{"player_id": int64(2), "hand_type": "三条", "equity": 0.4},
+			},
+		},
+		{
+			name:   "观战者看不到全押时公开的底牌",
+			event:  Event{Type: EventAllInRunout, Data: map[string]interface{}{"hands": hands()}},
+			seated: false,
+			wantHands: []map[string]interface{}{
+				{"player_id": int64(1), "hand_type": "一对", "equity": 0.6},
+				{"player_id": int64(2), "hand_type": "三条", "equity": 0.4},
+			},
+		},
+		{
+			name:   "没有底牌的事件原样推送",
+			event:  Event{Type: EventPlayerAction, Data: map[string]interface{}{"action": "call"}},
+			seated: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.RoomID = 7
+			tt.event.State = "river"
+
+			payload := tt.event.PayloadFor(tt.seated)
+			if payload["room_id"] != int64(7) || payload["state"] != "river" {
+				t.Errorf("消息缺少房间ID或游戏阶段: %v", payload)
+			}
+			for key, value := range tt.event.Data {
+				if key != "hands" && payload[key] != value {
+					t.Errorf("事件数据%s没有放在消息顶层: %v", key, payload)
+				}
+			}
+
+			got, _ := payload["hands"].([]map[string]interface{})
+			if !reflect.DeepEqual(got, tt.wantHands) {
+				t.Errorf("hands = %v，期望 %v", got, tt.wantHands)
+			}
+			if original := tt.event.Data["hands"]; original != nil && !reflect.DeepEqual(original, hands()) {
+				t.Errorf("生成观察者消息时修改了事件数据: %v", original)
+			}
+		})
+	}
+}
+
+func TestAllInEquityBudget(t *testing.T) {
+	tests := []struct {
+		name       string
+		gameType   poker.GameType
+		hands      []string
+		board      string
+		wantExact  bool
+		wantBoards int
+	}{
+		{name: "德州扑克翻牌后精确枚举", gameType: poker.TexasHoldem, hands: []string{"AsAh", "KdKc"}, board: "2c7d9h", wantExact: true, wantBoards: 990},
+		{name: "德州扑克翻牌前限制模拟次数", gameType: poker.TexasHoldem, hands: []string{"AsAh", "KdKc"}, wantBoards: liveEquityIterations},
+		{name: "奥马哈翻牌前限制模拟次数", gameType: poker.PotLimitOmaha, hands: []string{"AsAhKsKh", "QdQcJdJc", "9s8s7h6h"}, wantBoards: liveEquityIterations},
+		{name: "奥马哈高低牌翻牌后精确枚举", gameType: poker.OmahaHiLo, hands: []string{"AsAh2s3h", "KdKcQdQc"}, board: "4c7d9h", wantExact: true, wantBoards: 820},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRoom(1, "test", "low", 0, 1, 2, 6, false)
+			r.GameType = tt.gameType
+			r.CommunityCards = mustCards(t, tt.board)
+			for i, hand := range tt.hands {
+				r.Players[int64(i+1)] = &Player{ID: int64(i + 1), Position: i, Status: PlayerAllIn, Cards: mustCards(t, hand)}
+			}
+
+			hands, request := r.allInHands()
+			result, err := equity.Calculate(*request)
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			if result.Exact != tt.wantExact || result.Boards != tt.wantBoards {
+				t.Errorf("Exact = %v, Boards = %d，期望 %v, %d", result.Exact, result.Boards, tt.wantExact, tt.wantBoards)
+			}
+
+			event := Event{Data: map[string]interface{}{"hands": hands}, equity: request}
+			event.fillEquity()
+			for i, hand := range hands {
+				if _, ok := hand["equity"].(float64); !ok {
+					t.Errorf("第%d手没有填入胜率: %v", i+1, hand)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// 可验证公平洗牌（房间部分）
+// 作用：开局前公布服务器种子的承诺，按发牌顺序混合玩家的客户端种子推导牌堆，结束后揭示种子（此后本局的全部底牌即为公开），并可根据牌局记录复核发出的每一张牌
+
+package room
+
+import (
+	"fmt"
+
+	"texas-poker-backend/internal/game/poker"
+)
+
+// Fairness 一局的公平性数据
+type Fairness struct {
+	ServerSeed  string       `json:"server_seed"`  // 服务器种子（本局结束后公开）
+	SeedHash    string       `json:"seed_hash"`    // 开局前公布的服务器种子承诺
+	ClientSeeds []PlayerSeed `json:"client_seeds"` // 按发牌顺序排列的客户端种子
+}
+
+// PlayerSeed 玩家的客户端种子
+type PlayerSeed struct {
+	PlayerID int64  `json:"player_id"`
+	Seed     string `json:"seed"`
+}
+
+// Verification 牌局复核结果
+// 种子在牌局结束后公开，任何人都能据此推导出整副牌堆，因此一局的全部底牌在结束后都是公开的
+type Verification struct {
+	Valid           bool         `json:"valid"`
+	CommitmentValid bool         `json:"commitment_valid"` // 服务器种子与开局前公布的承诺一致
+	HoleCardsValid  bool         `json:"hole_cards_valid"` // 记录的底牌与种子推导的牌堆一致
+	BoardValid      bool         `json:"board_valid"`      // 记录的公共牌与种子推导的牌堆一致
+	SeedHash        string       `json:"seed_hash"`
+	ServerSeed      string       `json:"server_seed"`
+	ClientSeeds     []PlayerSeed `json:"client_seeds"`
+	Deck            []poker.Card `json:"deck"`               // 由种子推导出的牌堆顺序
+	Problems        []string     `json:"problems,omitempty"` // 与记录不一致的地方
+}
+
+// SetClientSeed 设置玩家的客户端种子，从下一局开始生效
+func (r *Room) SetClientSeed(userID int64, seed string) error {
+	if err := poker.ValidateClientSeed(seed); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.Players[userID]
+	if !exists {
+		return fmt.Errorf("玩家不在房间中")
+	}
+	player.ClientSeed = seed
+	return nil
+}
+
+// NextSeedHash 下一局服务器种子的承诺
+func (r *Room) NextSeedHash() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.nextSeedHash()
+}
+
+// nextSeedHash 下一局服务器种子的承诺（调用方需持有读锁）
+func (r *Room) nextSeedHash() string {
+	return poker.HashSeed(r.nextServerSeed)
+}
+
+// prepareDeck 按发牌顺序生成本局牌堆（调用方需持有写锁）
+// 使用已公布承诺的服务器种子并立即准备下一局的种子；设置了固定随机数源时不做公平性记录
+func (r *Room) prepareDeck() {
+	r.CurrentGame.DealOrder = r.actionOrder(r.DealerPosition)
+	r.CurrentGame.Fairness = nil
+
+	if r.replay != nil {
+		r.Deck = r.replay.deck(r.GameType, r.CurrentGame.DealOrder)
+		return
+	}
+
+	if r.rng != nil {
+		r.Deck = poker.NewDeckOfType(r.GameType.DeckType(), r.rng)
+		r.Deck.Shuffle()
+		return
+	}
+
+	fairness := &Fairness{SeedHash: r.nextSeedHash(), ServerSeed: r.nextServerSeed}
+	seeds := make([]string, 0, len(r.CurrentGame.DealOrder))
+	for _, playerID := range r.CurrentGame.DealOrder {
+		seed := r.Players[playerID].ClientSeed
+		fairness.ClientSeeds = append(fairness.ClientSeeds, PlayerSeed{PlayerID: playerID, Seed: seed})
+		seeds = append(seeds, seed)
+	}
+
+	r.Deck = poker.NewFairDeck(r.GameType.DeckType(), fairness.ServerSeed, seeds)
+	r.CurrentGame.Fairness = fairness
+	r.nextServerSeed = poker.GenerateServerSeed()
+}
+
+// VerifyHand 根据牌局记录复核服务器种子承诺，并重新推导牌堆检查发出的每一张牌
+// 发牌顺序：从庄家左手开始每人每轮一张直到发完底牌，之后每条街先烧一张牌再发公共牌
+func VerifyHand(record *HandRecord) (*Verification, error) {
+	if record.Fairness == nil {
+		return nil, fmt.Errorf("该牌局没有公平性记录")
+	}
+
+	gameType, err := poker.ParseGameType(record.GameType)
+	if err != nil {
+		return nil, err
+	}
+
+	fairness := record.Fairness
+	verification := &Verification{
+		SeedHash:    fairness.SeedHash,
+		ServerSeed:  fairness.ServerSeed,
+		ClientSeeds: fairness.ClientSeeds,
+	}
+	verification.CommitmentValid = poker.HashSeed(fairness.ServerSeed) == fairness.SeedHash
+	if !verification.CommitmentValid {
+		verification.Problems = append(verification.Problems, "服务器种子与开局前公布的承诺不一致")
+	}
+
+	seeds := make([]string, len(fairness.ClientSeeds))
+	for i, seed := range fairness.ClientSeeds {
+		seeds[i] = seed.Seed
+	}
+	deck := poker.NewFairDeck(gameType.DeckType(), fairness.ServerSeed, seeds)
+	verification.Deck = append([]poker.Card(nil), deck.GetAllCards()...)
+
+	players := make(map[int64]HandPlayer, len(record.Players))
+	for _, player := range record.Players {
+		players[player.ID] = player
+	}
+
+	// expect 检查下一张牌是否与记录一致
+	next := 0
+	expect := func(card poker.Card, what string) {
+		if next >= len(verification.Deck) {
+			verification.Problems = append(verification.Problems, fmt.Sprintf("%s超出牌堆", what))
+			return
+		}
+		if dealt := verification.Deck[next]; dealt != card {
+			verification.Problems = append(verification.Problems, fmt.Sprintf("%s应为%s，记录为%s", what, dealt, card))
+		}
+		next++
+	}
+
+	holeProblems := len(verification.Problems)
+	holeCards := 0
+	for _, seed := range fairness.ClientSeeds {
+		player, exists := players[seed.PlayerID]
+		if !exists {
+			verification.Problems = append(verification.Problems, fmt.Sprintf("玩家%d不在牌局记录中", seed.PlayerID))
+			continue
+		}
+		holeCards = max(holeCards, len(player.Cards))
+	}
+	for round := 0; round < holeCards; round++ {
+		for _, seed := range fairness.ClientSeeds {
+			if player, exists := players[seed.PlayerID]; exists && round < len(player.Cards) {
+				expect(player.Cards[round], fmt.Sprintf("玩家%d的第%d张底牌", player.ID, round+1))
+			}
+		}
+	}
+
+	verification.HoleCardsValid = len(verification.Problems) == holeProblems
+
+	// 翻牌3张、转牌1张、河牌1张，每条街前烧一张牌
+	boardProblems := len(verification.Problems)
+	for i, card := range record.Board {
+		if i == 0 || i >= 3 {
+			next++
+		}
+		expect(card, fmt.Sprintf("第%d张公共牌", i+1))
+	}
+	verification.BoardValid = len(verification.Problems) == boardProblems
+
+	verification.Valid = len(verification.Problems) == 0
+	return verification, nil
+}
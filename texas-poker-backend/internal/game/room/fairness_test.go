@@ -0,0 +1,102 @@
+// 可验证公平洗牌（房间部分）测试
+// 作用：校验按牌局记录复核种子承诺和发出的每一张牌
+
+package room
+
+import (
+	"testing"
+
+	"texas-poker-backend/internal/game/poker"
+)
+
+// fairRecord 种子为 "server-seed"、客户端种子为 alice、bob 的牌局记录
+// 牌堆依次为 7h Qd 2h Jd 6d 7c Jc 9h Js 3c……：玩家1拿到7h 2h，玩家2拿到Qd Jd，烧掉6d后翻牌为7c Jc 9h
+func fairRecord(t *testing.T) *HandRecord {
+	return &HandRecord{
+		GameType: string(poker.TexasHoldem),
+		Players: []HandPlayer{
+			{ID: 1, Position: 0, Cards: mustCards(t, "7h2h")},
+			{ID: 2, Position: 1, Cards: mustCards(t, "QdJd")},
+		},
+		Board: mustCards(t, "7cJc9h"),
+		Fairness: &Fairness{
+			ServerSeed:  "server-seed",
+			SeedHash:    poker.HashSeed("server-seed"),
+			ClientSeeds: []PlayerSeed{{PlayerID: 1, Seed: "alice"}, {PlayerID: 2, Seed: "bob"}},
+		},
+	}
+}
+
+func TestVerifyHand(t *testing.T) {
+	tests := []struct {
+		name           string
+		tamper         func(record *HandRecord)
+		wantCommitment bool
+		wantHoleCards  bool
+		wantBoard      bool
+	}{
+		{
+			name:           "记录与种子一致",
+			tamper:         func(record *HandRecord) {},
+			wantCommitment: true, wantHoleCards: true, wantBoard: true,
+		},
+		{
+			name:           "包含转牌",
+			tamper:         func(record *HandRecord) { record.Board = mustCards(t, "7cJc9h3c") },
+			wantCommitment: true, wantHoleCards: true, wantBoard: true,
+		},
+		{
+			name: "服务器种子与承诺不一致",
+			tamper: func(record *HandRecord) {
+				record.Fairness.SeedHash = poker.HashSeed("another-seed")
+			},
+			wantCommitment: false, wantHoleCards: true, wantBoard: true,
+		},
+		{
+			name:           "底牌被替换",
+			tamper:         func(record *HandRecord) { record.Players[1].Cards = mustCards(t, "AsAh") },
+			wantCommitment: true, wantHoleCards: false, wantBoard: true,
+		},
+		{
+			name:           "公共牌被替换",
+			tamper:         func(record *HandRecord) { record.Board = mustCards(t, "7cJcAs") },
+			wantCommitment: true, wantHoleCards: true, wantBoard: false,
+		},
+		{
+			name: "客户端种子的顺序被调换",
+			tamper: func(record *HandRecord) {
+				seeds := record.Fairness.ClientSeeds
+				seeds[0].Seed, seeds[1].Seed = seeds[1].Seed, seeds[0].Seed
+			},
+			wantCommitment: true, wantHoleCards: false, wantBoard: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := fairRecord(t)
+			tt.tamper(record)
+
+			verification, err := VerifyHand(record)
+			if err != nil {
+				t.Fatalf("VerifyHand: %v", err)
+			}
+			got := [3]bool{verification.CommitmentValid, verification.HoleCardsValid, verification.BoardValid}
+			want := [3]bool{tt.wantCommitment, tt.wantHoleCards, tt.wantBoard}
+			if got != want {
+				t.Errorf("承诺/底牌/公共牌 = %v，期望 %v，问题: %v", got, want, verification.Problems)
+			}
+			if wantValid := got == [3]bool{true, true, true}; verification.Valid != wantValid || (len(verification.Problems) == 0) != wantValid {
+				t.Errorf("Valid = %v，问题: %v", verification.Valid, verification.Problems)
+			}
+		})
+	}
+}
+
+func TestVerifyHandWithoutFairness(t *testing.T) {
+	record := fairRecord(t)
+	record.Fairness = nil
+	if _, err := VerifyHand(record); err == nil {
+		t.Errorf("没有公平性记录时期望返回错误")
+	}
+}
@@ -0,0 +1,193 @@
+// 牌局记录
+// 作用：在牌局进行中记录座位、底牌和每一次操作，结束时生成可持久化的结构化牌局记录
+
+package room
+
+import (
+	"time"
+
+	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/statemachine"
+)
+
+// 盲注在牌局记录中的操作标识
+const (
+	ActionPostSmallBlind = "post_small_blind"
+	ActionPostBigBlind   = "post_big_blind"
+)
+
+// HandRecord 一局完整的结构化记录（持久化为games.game_log）
+type HandRecord struct {
+	GameID         string       `json:"game_id"`
+	RoomID         int64        `json:"room_id"`
+	RoomName       string       `json:"room_name"`
+	GameType       string       `json:"game_type"`
+	SmallBlind     int          `json:"small_blind"`
+	BigBlind       int          `json:"big_blind"`
+	MaxPlayers     int          `json:"max_players"`
+	DealerPosition int          `json:"dealer_position"`
+	StartTime      time.Time    `json:"start_time"`
+	EndTime        time.Time    `json:"end_time"`
+	Players        []HandPlayer `json:"players"`
+	Actions        []HandAction `json:"actions"`
+	Board          []poker.Card `json:"board"`
+	Pots           []PotResult  `json:"pots"`
+	Pot            int          `json:"pot"` // 所有玩家投入的总筹码
+	Showdown       bool         `json:"showdown"`
+	WinnerID       int64        `json:"winner_id,omitempty"`
+	Fairness       *Fairness    `json:"fairness,omitempty"` // 公平洗牌的种子（用于复核发牌）
+	Log            []string     `json:"log"`                // 文本日志
+}
+
+// HandPlayer 牌局记录中的玩家
+type HandPlayer struct {
+	ID           int64                  `json:"id"`
+	Username     string                 `json:"username"`
+	Position     int                    `json:"position"`
+	StartChips   int                    `json:"start_chips"`
+	EndChips     int                    `json:"end_chips"`
+	ChipsChange  int                    `json:"chips_change"`
+	Contributed  int                    `json:"contributed"` // 本局投入的总筹码
+	Collected    int                    `json:"collected"`   // 本局从底池赢得的筹码
+	Cards        []poker.Card           `json:"cards"`
+	IsDealer     bool                   `json:"is_dealer"`
+	IsSmallBlind bool                   `json:"is_small_blind"`
+	IsBigBlind   bool                   `json:"is_big_blind"`
+	Folded       bool                   `json:"folded"`
+	HandType     string                 `json:"hand_type,omitempty"`   // 摊牌时的牌型
+	Description  *poker.HandDescription `json:"description,omitempty"` // 摊牌时牌型的中英文描述
+	LowHand      string                 `json:"low_hand,omitempty"`    // 高低牌玩法中摊牌时的合格低牌
+}
+
+// HandAction 牌局记录中的一次操作（包括盲注）
+type HandAction struct {
+	Street   string    `json:"street"` // 操作发生的阶段（preflop/flop/turn/river）
+	PlayerID int64     `json:"player_id"`
+	Action   string    `json:"action"`
+	Amount   int       `json:"amount"`       // 本次操作投入的筹码
+	To       int       `json:"to,omitempty"` // 操作后本轮的总下注
+	AllIn    bool      `json:"all_in,omitempty"`
+	Timeout  bool      `json:"timeout,omitempty"` // 超时后由系统代为操作
+	Time     time.Time `json:"time"`
+}
+
+// resolveAllIn 全押按其效果解析为下注、加注或跟注的操作标识，highest为操作前本轮的最高下注；其他操作原样返回
+func (a HandAction) resolveAllIn(highest int) string {
+	if a.Action != statemachine.AllIn.Key() {
+		return a.Action
+	}
+	switch {
+	case a.To <= highest:
+		return statemachine.Call.Key()
+	case highest == 0:
+		return statemachine.Bet.Key()
+	default:
+		return statemachine.Raise.Key()
+	}
+}
+
+// recordSeats 发牌后记录参与本局的玩家（调用方需持有写锁）
+func (r *Room) recordSeats() {
+	if r.CurrentGame == nil {
+		return
+	}
+
+	r.CurrentGame.Seats = r.CurrentGame.Seats[:0]
+	for _, player := range r.playersBySeat() {
+		if player.Status != PlayerActive {
+			continue
+		}
+		r.CurrentGame.Seats = append(r.CurrentGame.Seats, HandPlayer{
+			ID:           player.ID,
+			Username:     player.Username,
+			Position:     player.Position,
+			StartChips:   player.Chips,
+			Cards:        player.Cards,
+			IsDealer:     player.IsDealer,
+			IsSmallBlind: player.IsSmallBlind,
+			IsBigBlind:   player.IsBigBlind,
+		})
+	}
+}
+
+// recordAction 记录一次操作（调用方需持有写锁）
+func (r *Room) recordAction(playerID int64, action string, amount int, allIn, timeout bool) {
+	if r.CurrentGame == nil {
+		return
+	}
+
+	entry := HandAction{
+		Street:   r.StateMachine.GetCurrentState().Key(),
+		PlayerID: playerID,
+		Action:   action,
+		Amount:   amount,
+		AllIn:    allIn,
+		Timeout:  timeout,
+		Time:     time.Now(),
+	}
+	if r.BettingRound != nil && amount > 0 {
+		entry.To = r.BettingRound.GetPlayerBets()[playerID]
+	}
+	r.CurrentGame.Actions = append(r.CurrentGame.Actions, entry)
+}
+
+// buildHandRecord 结算后生成本局的完整记录（调用方需持有写锁）
+func (r *Room) buildHandRecord() *HandRecord {
+	game := r.CurrentGame
+	if game == nil {
+		return nil
+	}
+
+	collected := make(map[int64]int)
+	for _, result := range game.PotResults {
+		for playerID, share := range result.Shares {
+			collected[playerID] += share
+		}
+	}
+
+	record := &HandRecord{
+		GameID:         game.ID,
+		RoomID:         r.ID,
+		RoomName:       r.Name,
+		GameType:       string(r.GameType),
+		SmallBlind:     r.SmallBlind,
+		BigBlind:       r.BigBlind,
+		MaxPlayers:     r.MaxPlayers,
+		DealerPosition: r.DealerPosition,
+		StartTime:      game.StartTime,
+		EndTime:        time.Now(),
+		Players:        make([]HandPlayer, 0, len(game.Seats)),
+		Actions:        game.Actions,
+		Board:          r.CommunityCards,
+		Pots:           game.PotResults,
+		Showdown:       r.ShowdownReached,
+		WinnerID:       game.WinnerID,
+		Fairness:       game.Fairness,
+		Log:            game.GameLog,
+	}
+
+	for _, seat := range game.Seats {
+		seat.Contributed = game.Contributions[seat.ID]
+		seat.Collected = collected[seat.ID]
+		seat.ChipsChange = seat.Collected - seat.Contributed
+		seat.EndChips = seat.StartChips + seat.ChipsChange
+		record.Pot += seat.Contributed
+
+		// 已离开房间的玩家视为弃牌
+		player, exists := r.Players[seat.ID]
+		seat.Folded = !exists || player.Status == PlayerFolded
+		if r.ShowdownReached && !seat.Folded {
+			if hand, ok := r.evaluatePlayerHand(seat.Cards); ok {
+				seat.HandType = hand.Type.String()
+				description := poker.DescribeHand(hand, seat.Cards)
+				seat.Description = &description
+			}
+			if low, ok := r.evaluatePlayerLow(seat.Cards); ok {
+				seat.LowHand = low.String()
+			}
+		}
+		record.Players = append(record.Players, seat)
+	}
+
+	return record
+}
@@ -0,0 +1,125 @@
+// 房间注册表
+// 作用：在进程内维护所有活跃的房间实例，提供按ID查找、按玩家查找和列表功能，并按用户串行化入座等操作
+
+package room
+
+import (
+	"sort"
+	"sync"
+)
+
+// Manager 房间注册表
+type Manager struct {
+	rooms map[int64]*Room
+	mu    sync.RWMutex
+
+	userLocks map[int64]*userLock // 正在使用的用户锁
+	userMu    sync.Mutex
+}
+
+// userLock 单个用户的锁，没有持有者和等待者时从注册表中删除
+type userLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewManager 创建新的房间注册表
+func NewManager() *Manager {
+	return &Manager{
+		rooms:     make(map[int64]*Room),
+		userLocks: make(map[int64]*userLock),
+	}
+}
+
+// Add 注册房间实例，如果已存在同ID的房间则返回已存在的实例
+func (m *Manager) Add(room *Room) *Room {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.rooms[room.ID]; ok {
+		return existing
+	}
+	m.rooms[room.ID] = room
+	return room
+}
+
+// Get 根据ID获取房间实例
+func (m *Manager) Get(id int64) (*Room, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	room, ok := m.rooms[id]
+	return room, ok
+}
+
+// Remove 移除房间实例，并取消该房间尚未触发的自动开局
+func (m *Manager) Remove(id int64) {
+	m.mu.Lock()
+	room, ok := m.rooms[id]
+	delete(m.rooms, id)
+	m.mu.Unlock()
+
+	if ok {
+		room.CancelNextHand()
+	}
+}
+
+// List 获取所有房间实例（按ID排序）
+func (m *Manager) List() []*Room {
+	m.mu.RLock()
+	rooms := make([]*Room, 0, len(m.rooms))
+	for _, room := range m.rooms {
+		rooms = append(rooms, room)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].ID < rooms[j].ID
+	})
+	return rooms
+}
+
+// FindPlayerRoom 查找玩家当前所在的房间
+func (m *Manager) FindPlayerRoom(userID int64) (*Room, bool) {
+	for _, room := range m.List() {
+		if room.HasPlayer(userID) {
+			return room, true
+		}
+	}
+	return nil, false
+}
+
+// HasUnsettledChips 检查玩家在任一房间中是否还有未结算的筹码
+func (m *Manager) HasUnsettledChips(userID int64) bool {
+	for _, room := range m.List() {
+		if room.HasUnsettledChips(userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// LockUser 锁定用户，串行化同一用户的入座和管理员筹码调整，返回解锁函数
+// 查找玩家所在房间到入座之间没有其他房间的锁保护，不加锁时同一用户可能同时坐进两个房间
+func (m *Manager) LockUser(userID int64) func() {
+	m.userMu.Lock()
+	lock, exists := m.userLocks[userID]
+	if !exists {
+		lock = &userLock{}
+		m.userLocks[userID] = lock
+	}
+	lock.refs++
+	m.userMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		m.userMu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(m.userLocks, userID)
+		}
+		m.userMu.Unlock()
+	}
+}
@@ -0,0 +1,47 @@
+// 房间注册表测试
+// 作用：校验同一用户的操作被串行化、不同用户互不阻塞，以及用户锁在释放后被清理
+
+package room
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManagerLockUser(t *testing.T) {
+	m := NewManager()
+
+	unlock := m.LockUser(1)
+
+	// 不同用户不受影响
+	m.LockUser(2)()
+
+	acquired := make(chan struct{})
+	released := make(chan struct{})
+	go func() {
+		release := m.LockUser(1)
+		close(acquired)
+		release()
+		close(released)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("同一用户的锁未释放时不应被再次获得")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatalf("释放后等待者应获得锁")
+	}
+	<-released
+
+	m.userMu.Lock()
+	defer m.userMu.Unlock()
+	if len(m.userLocks) != 0 {
+		t.Errorf("全部释放后仍有 %d 个用户锁", len(m.userLocks))
+	}
+}
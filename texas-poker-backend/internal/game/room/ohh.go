@@ -0,0 +1,283 @@
+// Open Hand History
+// 作用：定义OHH（Open Hand History）JSON格式的牌局结构，并将持久化的牌局记录导出为OHH；只写出导出者本人的底牌，摊牌亮出的牌除外
+
+package room
+
+import (
+	"strconv"
+	"time"
+
+	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/statemachine"
+)
+
+// OHHSpecVersion 导出遵循的OHH规范版本
+const OHHSpecVersion = "1.4.6"
+
+// OHHSiteName 导出时的站点名称
+const OHHSiteName = "Texas Poker"
+
+// OHH中的下注街
+const (
+	OHHPreflop  = "Preflop"
+	OHHFlop     = "Flop"
+	OHHTurn     = "Turn"
+	OHHRiver    = "River"
+	OHHShowdown = "Showdown"
+)
+
+// OHH中的操作
+const (
+	OHHDealtCards = "Dealt Cards"
+	OHHMucksCards = "Mucks Cards"
+	OHHShowsCards = "Shows Cards"
+	OHHPostSB     = "Post SB"
+	OHHPostBB     = "Post BB"
+	OHHFold       = "Fold"
+	OHHCheck      = "Check"
+	OHHBet        = "Bet"
+	OHHRaise      = "Raise"
+	OHHCall       = "Call"
+)
+
+// OHHDocument OHH文件中的一局（{"ohh": {...}}），多局之间以空行分隔
+type OHHDocument struct {
+	OHH OHHHand `json:"ohh"`
+}
+
+// OHHHand OHH格式的一局牌
+// 金额为筹码数；OHH没有短牌的游戏类型，短牌导出为Holdem并在扩展字段variant中标明
+type OHHHand struct {
+	SpecVersion      string      `json:"spec_version"`
+	SiteName         string      `json:"site_name"`
+	NetworkName      string      `json:"network_name"`
+	InternalVersion  string      `json:"internal_version"`
+	Tournament       bool        `json:"tournament"`
+	GameNumber       string      `json:"game_number"`
+	StartDateUTC     time.Time   `json:"start_date_utc"`
+	TableName        string      `json:"table_name"`
+	GameType         string      `json:"game_type"` // Holdem、Omaha、OmahaHiLo
+	Variant          string      `json:"variant,omitempty"`
+	BetLimit         OHHBetLimit `json:"bet_limit"`
+	TableSize        int         `json:"table_size"`
+	Currency         string      `json:"currency"`
+	DealerSeat       int         `json:"dealer_seat"`
+	SmallBlindAmount float64     `json:"small_blind_amount"`
+	BigBlindAmount   float64     `json:"big_blind_amount"`
+	AnteAmount       float64     `json:"ante_amount"`
+	HeroPlayerID     int64       `json:"hero_player_id,omitempty"`
+	Players          []OHHPlayer `json:"players"`
+	Rounds           []OHHRound  `json:"rounds"`
+	Pots             []OHHPot    `json:"pots"`
+}
+
+// OHHBetLimit 限注类型（NL无限注、PL底池限注、FL固定限注）
+type OHHBetLimit struct {
+	BetType string  `json:"bet_type"`
+	BetCap  float64 `json:"bet_cap"`
+}
+
+// OHHPlayer OHH中的玩家（座位从1开始）
+type OHHPlayer struct {
+	ID            int64   `json:"id"`
+	Seat          int     `json:"seat"`
+	Name          string  `json:"name"`
+	Display       string  `json:"display,omitempty"`
+	StartingStack float64 `json:"starting_stack"`
+	IsSittingOut  bool    `json:"is_sitting_out,omitempty"`
+}
+
+// OHHRound OHH中的一条街，cards为这条街新发出的公共牌
+type OHHRound struct {
+	ID      int          `json:"id"`
+	Street  string       `json:"street"`
+	Cards   []poker.Card `json:"cards,omitempty"`
+	Actions []OHHAction  `json:"actions"`
+}
+
+// OHHAction OHH中的一次操作，amount为本次操作投入的筹码（加注时为补到新下注额所投入的部分）
+type OHHAction struct {
+	ActionNumber int          `json:"action_number"`
+	PlayerID     int64        `json:"player_id"`
+	Action       string       `json:"action"`
+	Amount       float64      `json:"amount,omitempty"`
+	IsAllIn      bool         `json:"is_allin,omitempty"`
+	Cards        []poker.Card `json:"cards,omitempty"`
+}
+
+// OHHPot OHH中的底池（number为0时是主池），不包含未被跟注而退还的筹码
+type OHHPot struct {
+	Number     int            `json:"number"`
+	Amount     float64        `json:"amount"`
+	Rake       float64        `json:"rake"`
+	Jackpot    float64        `json:"jackpot"`
+	PlayerWins []OHHPlayerWin `json:"player_wins"`
+}
+
+// OHHPlayerWin 玩家从底池中赢得的筹码
+type OHHPlayerWin struct {
+	PlayerID        int64   `json:"player_id"`
+	WinAmount       float64 `json:"win_amount"`
+	ContributedRake float64 `json:"contributed_rake"`
+}
+
+// ohhStreets 下注街在牌局记录和OHH中的名称及发出的公共牌数
+var ohhStreets = []struct {
+	key   string
+	name  string
+	board int
+}{
+	{"preflop", OHHPreflop, 0},
+	{"flop", OHHFlop, 3},
+	{"turn", OHHTurn, 4},
+	{"river", OHHRiver, 5},
+}
+
+// ohhGameType 游戏类型在OHH中的game_type、扩展字段variant和限注类型
+func ohhGameType(gameType poker.GameType) (string, string, string) {
+	switch gameType {
+	case poker.ShortDeckHoldem:
+		return "Holdem", string(poker.ShortDeckHoldem), "NL"
+	case poker.PotLimitOmaha:
+		return "Omaha", "", "PL"
+	case poker.OmahaHiLo:
+		return "OmahaHiLo", "", "PL"
+	default:
+		return "Holdem", "", "NL"
+	}
+}
+
+// FormatOHH 将一局记录转换为OHH格式
+// handNumber为牌局的数字编号（games.id），heroID为导出者，只有其底牌会以 "Dealt Cards" 写出
+func FormatOHH(record *HandRecord, handNumber, heroID int64) *OHHDocument {
+	gameType, err := poker.ParseGameType(record.GameType)
+	if err != nil {
+		gameType = poker.TexasHoldem
+	}
+	name, variant, betType := ohhGameType(gameType)
+
+	hand := OHHHand{
+		SpecVersion:      OHHSpecVersion,
+		SiteName:         OHHSiteName,
+		NetworkName:      OHHSiteName,
+		GameNumber:       strconv.FormatInt(handNumber, 10),
+		StartDateUTC:     record.StartTime.UTC(),
+		TableName:        record.RoomName,
+		GameType:         name,
+		Variant:          variant,
+		BetLimit:         OHHBetLimit{BetType: betType},
+		TableSize:        record.MaxPlayers,
+		Currency:         "CHIPS",
+		SmallBlindAmount: float64(record.SmallBlind),
+		BigBlindAmount:   float64(record.BigBlind),
+		Players:          make([]OHHPlayer, 0, len(record.Players)),
+		Rounds:           make([]OHHRound, 0, len(ohhStreets)+1),
+	}
+
+	var hero *HandPlayer
+	for i, player := range record.Players {
+		hand.Players = append(hand.Players, OHHPlayer{
+			ID:            player.ID,
+			Seat:          player.Position + 1,
+			Name:          player.Username,
+			Display:       player.Username,
+			StartingStack: float64(player.StartChips),
+		})
+		if player.IsDealer {
+			hand.DealerSeat = player.Position + 1
+		}
+		if player.ID == heroID {
+			hand.HeroPlayerID = heroID
+			hero = &record.Players[i]
+		}
+	}
+
+	number := 0
+	appendAction := func(round *OHHRound, action OHHAction) {
+		number++
+		action.ActionNumber = number
+		round.Actions = append(round.Actions, action)
+	}
+
+	dealt := 0
+	for _, street := range ohhStreets {
+		if len(record.Board) < street.board {
+			break
+		}
+		round := OHHRound{ID: len(hand.Rounds), Street: street.name, Actions: make([]OHHAction, 0)}
+		if street.board > dealt {
+			round.Cards = record.Board[dealt:street.board]
+			dealt = street.board
+		}
+
+		highest := 0
+		holeCardsDealt := street.key != "preflop"
+		for _, action := range record.Actions {
+			if action.Street != street.key {
+				continue
+			}
+			isBlind := action.Action == ActionPostSmallBlind || action.Action == ActionPostBigBlind
+			if !isBlind && !holeCardsDealt {
+				if hero != nil && len(hero.Cards) > 0 {
+					appendAction(&round, OHHAction{PlayerID: hero.ID, Action: OHHDealtCards, Cards: hero.Cards})
+				}
+				holeCardsDealt = true
+			}
+
+			appendAction(&round, OHHAction{
+				PlayerID: action.PlayerID,
+				Action:   ohhAction(action.resolveAllIn(highest)),
+				Amount:   float64(action.Amount),
+				IsAllIn:  action.AllIn,
+			})
+			highest = max(highest, action.To)
+		}
+		if !holeCardsDealt && hero != nil && len(hero.Cards) > 0 {
+			appendAction(&round, OHHAction{PlayerID: hero.ID, Action: OHHDealtCards, Cards: hero.Cards})
+		}
+		hand.Rounds = append(hand.Rounds, round)
+	}
+
+	if record.Showdown {
+		round := OHHRound{ID: len(hand.Rounds), Street: OHHShowdown, Actions: make([]OHHAction, 0)}
+		for _, player := range record.Players {
+			if !player.Folded {
+				appendAction(&round, OHHAction{PlayerID: player.ID, Action: OHHShowsCards, Cards: player.Cards})
+			}
+		}
+		hand.Rounds = append(hand.Rounds, round)
+	}
+
+	uncalledID, uncalled := uncalledBet(record.Players)
+	for i, pot := range collectedPots(record.Pots, uncalledID, uncalled) {
+		ohhPot := OHHPot{Number: i, Amount: float64(pot.Amount), PlayerWins: make([]OHHPlayerWin, 0, len(pot.Shares))}
+		for _, player := range record.Players {
+			if share := pot.Shares[player.ID]; share > 0 {
+				ohhPot.PlayerWins = append(ohhPot.PlayerWins, OHHPlayerWin{PlayerID: player.ID, WinAmount: float64(share)})
+			}
+		}
+		hand.Pots = append(hand.Pots, ohhPot)
+	}
+
+	return &OHHDocument{OHH: hand}
+}
+
+// ohhAction 牌局记录中的操作标识在OHH中的名称（全押需先解析为下注、加注或跟注）
+func ohhAction(action string) string {
+	switch action {
+	case ActionPostSmallBlind:
+		return OHHPostSB
+	case ActionPostBigBlind:
+		return OHHPostBB
+	case statemachine.Fold.Key():
+		return OHHFold
+	case statemachine.Check.Key():
+		return OHHCheck
+	case statemachine.Call.Key():
+		return OHHCall
+	case statemachine.Bet.Key():
+		return OHHBet
+	default:
+		return OHHRaise
+	}
+}
@@ -0,0 +1,147 @@
+// OHH导出与重放测试
+// 作用：校验牌局记录导出为OHH后用引擎重放能得到一致的结算和相同的OHH，结算不一致或操作无效时给出提示，以及导入牌局按用户名关联用户（同名的玩家不关联）
+
+package room
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOHHRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(t *testing.T) *HandRecord
+		hero   int64
+	}{
+		{name: "翻牌圈下注未被跟注", record: uncalledRecord, hero: 1},
+		{name: "全押形成边池后摊牌", record: sidePotRecord, hero: 1},
+		{name: "导出者为弃牌的玩家", record: uncalledRecord, hero: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.record(t)
+			doc := FormatOHH(original, 42, tt.hero)
+
+			record, mismatches, err := ReplayOHH(&doc.OHH)
+			if err != nil {
+				t.Fatalf("ReplayOHH: %v", err)
+			}
+			if len(mismatches) != 0 {
+				t.Errorf("结算不一致: %v", mismatches)
+			}
+
+			// OHH中的玩家ID保持不变，重放的记录再次导出应与原OHH相同
+			for i, player := range record.Players {
+				want := original.Players[i]
+				if player.ID != want.ID || player.Contributed != want.Contributed || player.Collected != want.Collected || player.Folded != want.Folded {
+					t.Errorf("第%d位玩家 = %+v，期望ID %d、投入%d、赢得%d、弃牌%v",
+						i+1, player, want.ID, want.Contributed, want.Collected, want.Folded)
+				}
+			}
+			if record.Showdown != original.Showdown {
+				t.Errorf("Showdown = %v，期望 %v", record.Showdown, original.Showdown)
+			}
+			if got := FormatOHH(record, 42, tt.hero); !reflect.DeepEqual(got, doc) {
+				t.Errorf("重放后导出的OHH与原OHH不同\n实际: %+v\n期望: %+v", got.OHH, doc.OHH)
+			}
+		})
+	}
+}
+
+func TestReplayOHHMismatch(t *testing.T) {
+	doc := FormatOHH(sidePotRecord(t), 42, 1)
+	// 主池记为bob赢得，与引擎的摊牌结果不一致
+	doc.OHH.Pots[0].PlayerWins[0].PlayerID = 2
+
+	_, mismatches, err := ReplayOHH(&doc.OHH)
+	if err != nil {
+		t.Fatalf("ReplayOHH: %v", err)
+	}
+	want := []string{"玩家bob应赢得300，引擎结算为0", "玩家carol应赢得400，引擎结算为700"}
+	if !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("mismatches = %q，期望 %q", mismatches, want)
+	}
+}
+
+func TestReplayOHHInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(hand *OHHHand)
+	}{
+		{name: "不支持前注", modify: func(hand *OHHHand) { hand.AnteAmount = 1 }},
+		{name: "不支持固定限注", modify: func(hand *OHHHand) { hand.BetLimit.BetType = "FL" }},
+		{name: "只有一名玩家", modify: func(hand *OHHHand) { hand.Players = hand.Players[:1] }},
+		{name: "座位号重复", modify: func(hand *OHHHand) { hand.Players[1].Seat = 1 }},
+		{name: "座位号超过最大桌子人数", modify: func(hand *OHHHand) { hand.Players[2].Seat = maxOHHTableSize + 1 }},
+		{name: "桌子人数超过上限", modify: func(hand *OHHHand) { hand.TableSize = 1 << 20 }},
+		{name: "不是该玩家的轮次", modify: func(hand *OHHHand) { hand.Rounds[1].Actions[0].PlayerID = 1 }},
+		{name: "操作不完整", modify: func(hand *OHHHand) { hand.Rounds[1].Actions = hand.Rounds[1].Actions[:2] }},
+		{name: "重复的牌", modify: func(hand *OHHHand) { hand.Rounds[1].Cards[0] = hand.Rounds[1].Cards[1] }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := FormatOHH(uncalledRecord(t), 42, 1)
+			tt.modify(&doc.OHH)
+			if _, _, err := ReplayOHH(&doc.OHH); err == nil {
+				t.Errorf("期望返回错误")
+			}
+		})
+	}
+}
+
+func TestLinkUsers(t *testing.T) {
+	record := uncalledRecord(t)
+	linked := record.LinkUsers(map[string]int64{"alice": 101, "carol": 303})
+
+	var linkedIDs []int64
+	for _, player := range linked {
+		linkedIDs = append(linkedIDs, player.ID)
+	}
+	if want := []int64{101, 303}; !reflect.DeepEqual(linkedIDs, want) {
+		t.Errorf("关联的玩家 = %v，期望 %v", linkedIDs, want)
+	}
+
+	// 未匹配的bob换为负数ID，操作和底池中的玩家ID同步替换
+	var playerIDs []int64
+	for _, player := range record.Players {
+		playerIDs = append(playerIDs, player.ID)
+	}
+	if want := []int64{101, -2, 303}; !reflect.DeepEqual(playerIDs, want) {
+		t.Errorf("玩家ID = %v，期望 %v", playerIDs, want)
+	}
+
+	var actionIDs []int64
+	for _, action := range record.Actions {
+		actionIDs = append(actionIDs, action.PlayerID)
+	}
+	if want := []int64{-2, 303, 101, -2, 303, 303, 101, 303}; !reflect.DeepEqual(actionIDs, want) {
+		t.Errorf("操作的玩家ID = %v，期望 %v", actionIDs, want)
+	}
+
+	pot := record.Pots[0]
+	if !reflect.DeepEqual(pot.Eligible, []int64{101}) || !reflect.DeepEqual(pot.Winners, []int64{101}) ||
+		!reflect.DeepEqual(pot.Shares, map[int64]int{101: 105}) {
+		t.Errorf("底池 = %+v，期望只有101有资格并赢得105", pot)
+	}
+}
+
+func TestLinkUsersDuplicateNames(t *testing.T) {
+	record := uncalledRecord(t)
+	record.Players[2].Username = "alice"
+
+	linked := record.LinkUsers(map[string]int64{"alice": 101})
+	if len(linked) != 0 {
+		t.Errorf("同名的玩家不应关联到用户: %+v", linked)
+	}
+
+	var playerIDs []int64
+	for _, player := range record.Players {
+		playerIDs = append(playerIDs, player.ID)
+	}
+	if want := []int64{-1, -2, -3}; !reflect.DeepEqual(playerIDs, want) {
+		t.Errorf("玩家ID = %v，期望 %v", playerIDs, want)
+	}
+}
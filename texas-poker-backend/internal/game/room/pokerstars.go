@@ -0,0 +1,378 @@
+// PokerStars牌局历史
+// 作用：将持久化的牌局记录转换为PokerStars文本格式，供HM3、PokerTracker等软件导入；只写出导出者本人的底牌，摊牌亮出的牌除外
+
+package room
+
+import (
+	"fmt"
+	"strings"
+
+	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/statemachine"
+)
+
+// pokerStarsTimeLayout PokerStars牌局历史中的时间格式
+const pokerStarsTimeLayout = "2006/01/02 15:04:05"
+
+// pokerStarsStreets 下注街及其在PokerStars中的名称和发出的公共牌数
+var pokerStarsStreets = []struct {
+	key   string
+	name  string
+	board int
+}{
+	{"preflop", "", 0},
+	{"flop", "FLOP", 3},
+	{"turn", "TURN", 4},
+	{"river", "RIVER", 5},
+}
+
+// pokerStarsGame 游戏类型在PokerStars牌局历史中的名称
+func pokerStarsGame(gameType poker.GameType) string {
+	switch gameType {
+	case poker.ShortDeckHoldem:
+		return "6+ Hold'em No Limit"
+	case poker.PotLimitOmaha:
+		return "Omaha Pot Limit"
+	case poker.OmahaHiLo:
+		return "Omaha Hi/Lo Pot Limit"
+	default:
+		return "Hold'em No Limit"
+	}
+}
+
+// FormatPokerStars 将一局记录转换为PokerStars格式的牌局历史
+// handNumber为牌局的数字编号（games.id），heroID为导出者，只有其底牌会以 "Dealt to" 写出
+func FormatPokerStars(record *HandRecord, handNumber, heroID int64) string {
+	gameType, err := poker.ParseGameType(record.GameType)
+	if err != nil {
+		gameType = poker.TexasHoldem
+	}
+
+	names := make(map[int64]string, len(record.Players))
+	seats := make(map[int64]int, len(record.Players))
+	button := 0
+	for _, player := range record.Players {
+		names[player.ID] = player.Username
+		seats[player.ID] = player.Position + 1
+		if player.IsDealer {
+			button = player.Position + 1
+		}
+	}
+
+	uncalledID, uncalled := uncalledBet(record.Players)
+	pots := collectedPots(record.Pots, uncalledID, uncalled)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "PokerStars Hand #%d:  %s (%d/%d) - %s UTC\n",
+		handNumber, pokerStarsGame(gameType), record.SmallBlind, record.BigBlind,
+		record.StartTime.UTC().Format(pokerStarsTimeLayout))
+	fmt.Fprintf(&b, "Table '%s' %d-max Seat #%d is the button\n", record.RoomName, record.MaxPlayers, button)
+	for _, player := range record.Players {
+		fmt.Fprintf(&b, "Seat %d: %s (%d in chips)\n", player.Position+1, player.Username, player.StartChips)
+	}
+
+	// 未被跟注的部分在最后一次操作之后退还
+	lastAction := len(record.Actions) - 1
+	foldStreets := make(map[int64]string)
+
+	for _, street := range pokerStarsStreets {
+		if street.board > 0 {
+			if len(record.Board) < street.board {
+				break
+			}
+			if street.board == 3 {
+				fmt.Fprintf(&b, "*** FLOP *** %s\n", pokerStarsCards(record.Board[:3]))
+			} else {
+				fmt.Fprintf(&b, "*** %s *** %s %s\n", street.name,
+					pokerStarsCards(record.Board[:street.board-1]), pokerStarsCards(record.Board[street.board-1:street.board]))
+			}
+		}
+
+		highest := 0
+		holeCardsDealt := street.key != "preflop"
+		for i, action := range record.Actions {
+			if action.Street != street.key {
+				continue
+			}
+			isBlind := action.Action == ActionPostSmallBlind || action.Action == ActionPostBigBlind
+			if !isBlind && !holeCardsDealt {
+				writeHoleCards(&b, record.Players, heroID)
+				holeCardsDealt = true
+			}
+
+			line := pokerStarsAction(action, highest)
+			if action.AllIn {
+				line += " and is all-in"
+			}
+			fmt.Fprintf(&b, "%s: %s\n", names[action.PlayerID], line)
+			highest = max(highest, action.To)
+
+			if action.Action == statemachine.Fold.Key() {
+				foldStreets[action.PlayerID] = street.key
+			}
+			if i == lastAction && uncalled > 0 {
+				fmt.Fprintf(&b, "Uncalled bet (%d) returned to %s\n", uncalled, names[uncalledID])
+			}
+		}
+		if !holeCardsDealt {
+			writeHoleCards(&b, record.Players, heroID)
+		}
+	}
+
+	if record.Showdown {
+		b.WriteString("*** SHOW DOWN ***\n")
+		for _, player := range record.Players {
+			if !player.Folded {
+				fmt.Fprintf(&b, "%s: shows %s (%s)\n", player.Username, pokerStarsCards(player.Cards),
+					pokerStarsHandText(gameType, player.Cards, record.Board))
+			}
+		}
+	}
+
+	// PokerStars先列边池再列主池
+	for i := len(pots) - 1; i >= 0; i-- {
+		for _, player := range record.Players {
+			if share := pots[i].Shares[player.ID]; share > 0 {
+				fmt.Fprintf(&b, "%s collected %d from %s\n", player.Username, share, potName(pots, i, false))
+			}
+		}
+	}
+
+	b.WriteString("*** SUMMARY ***\n")
+	total := record.Pot - uncalled
+	fmt.Fprintf(&b, "Total pot %d", total)
+	if len(pots) > 1 {
+		for i, pot := range pots {
+			fmt.Fprintf(&b, " %s %d.", potName(pots, i, true), pot.Amount)
+		}
+	}
+	b.WriteString(" | Rake 0\n")
+	if len(record.Board) > 0 {
+		fmt.Fprintf(&b, "Board %s\n", pokerStarsCards(record.Board))
+	}
+
+	for _, player := range record.Players {
+		fmt.Fprintf(&b, "Seat %d: %s%s %s\n", seats[player.ID], player.Username, pokerStarsRoles(player),
+			pokerStarsSummary(gameType, record, player, foldStreets[player.ID], uncalledID, uncalled))
+	}
+
+	return b.String()
+}
+
+// writeHoleCards 写出 "*** HOLE CARDS ***" 和导出者本人的底牌
+func writeHoleCards(b *strings.Builder, players []HandPlayer, heroID int64) {
+	b.WriteString("*** HOLE CARDS ***\n")
+	for _, player := range players {
+		if player.ID == heroID && len(player.Cards) > 0 {
+			fmt.Fprintf(b, "Dealt to %s %s\n", player.Username, pokerStarsCards(player.Cards))
+		}
+	}
+}
+
+// pokerStarsAction 一次操作在PokerStars中的写法，highest为操作前本轮的最高下注（全押按其效果写为下注、加注或跟注）
+func pokerStarsAction(action HandAction, highest int) string {
+	switch action.resolveAllIn(highest) {
+	case ActionPostSmallBlind:
+		return fmt.Sprintf("posts small blind %d", action.Amount)
+	case ActionPostBigBlind:
+		return fmt.Sprintf("posts big blind %d", action.Amount)
+	case statemachine.Fold.Key():
+		return "folds"
+	case statemachine.Check.Key():
+		return "checks"
+	case statemachine.Call.Key():
+		return fmt.Sprintf("calls %d", action.Amount)
+	case statemachine.Bet.Key():
+		return fmt.Sprintf("bets %d", action.Amount)
+	default:
+		return fmt.Sprintf("raises %d to %d", action.To-highest, action.To)
+	}
+}
+
+// uncalledBet 计算未被跟注而退还的筹码：投入最多的玩家超出第二多投入的部分
+func uncalledBet(players []HandPlayer) (int64, int) {
+	var topID int64
+	top, second := 0, 0
+	for _, player := range players {
+		switch {
+		case player.Contributed > top:
+			topID, top, second = player.ID, player.Contributed, top
+		case player.Contributed > second:
+			second = player.Contributed
+		}
+	}
+	return topID, top - second
+}
+
+// collectedPots 从结算结果中扣除未被跟注的部分（它计在只有该玩家有资格的最后一个底池里），并去掉扣除后为空的底池
+func collectedPots(results []PotResult, uncalledID int64, uncalled int) []PotResult {
+	pots := make([]PotResult, 0, len(results))
+	for i, result := range results {
+		if uncalled > 0 && i == len(results)-1 && len(result.Eligible) == 1 && result.Eligible[0] == uncalledID {
+			refund := min(uncalled, result.Amount)
+			shares := make(map[int64]int, len(result.Shares))
+			for playerID, share := range result.Shares {
+				shares[playerID] = share
+			}
+			shares[uncalledID] -= refund
+			result.Amount -= refund
+			result.Shares = shares
+		}
+		if result.Amount > 0 {
+			pots = append(pots, result)
+		}
+	}
+	return pots
+}
+
+// potName 底池在PokerStars中的名称（只有一个底池时为 "pot"）
+func potName(pots []PotResult, i int, title bool) string {
+	var name string
+	switch {
+	case len(pots) == 1:
+		name = "pot"
+	case i == 0:
+		name = "main pot"
+	case len(pots) == 2:
+		name = "side pot"
+	default:
+		name = fmt.Sprintf("side pot-%d", i)
+	}
+	if title {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	return name
+}
+
+// pokerStarsRoles 总结中座位后的位置标记
+func pokerStarsRoles(player HandPlayer) string {
+	var roles string
+	if player.IsDealer {
+		roles += " (button)"
+	}
+	if player.IsSmallBlind {
+		roles += " (small blind)"
+	}
+	if player.IsBigBlind {
+		roles += " (big blind)"
+	}
+	return roles
+}
+
+// pokerStarsSummary 总结中每个座位的结果
+func pokerStarsSummary(gameType poker.GameType, record *HandRecord, player HandPlayer, foldStreet string, uncalledID int64, uncalled int) string {
+	collected := player.Collected
+	if player.ID == uncalledID {
+		collected -= uncalled
+	}
+
+	switch {
+	case player.Folded:
+		switch foldStreet {
+		case "flop":
+			return "folded on the Flop"
+		case "turn":
+			return "folded on the Turn"
+		case "river":
+			return "folded on the River"
+		}
+		if player.Contributed == 0 {
+			return "folded before Flop (didn't bet)"
+		}
+		return "folded before Flop"
+	case record.Showdown:
+		text := pokerStarsHandText(gameType, player.Cards, record.Board)
+		if collected > 0 {
+			return fmt.Sprintf("showed %s and won (%d) with %s", pokerStarsCards(player.Cards), collected, text)
+		}
+		return fmt.Sprintf("showed %s and lost with %s", pokerStarsCards(player.Cards), text)
+	case collected > 0:
+		return fmt.Sprintf("collected (%d)", collected)
+	default:
+		return "mucked"
+	}
+}
+
+// pokerStarsCards 牌组在PokerStars中的写法（如 "[As Kd]"）
+func pokerStarsCards(cards []poker.Card) string {
+	notations := make([]string, len(cards))
+	for i, card := range cards {
+		notations[i] = card.Notation()
+	}
+	return "[" + strings.Join(notations, " ") + "]"
+}
+
+// pokerStarsHandText 摊牌牌型在PokerStars中的写法（如 "two pair, Kings and Sevens"），高低牌玩法附上低牌
+func pokerStarsHandText(gameType poker.GameType, hole, board []poker.Card) string {
+	hand, err := gameType.Evaluate(hole, board)
+	if err != nil {
+		return "unknown"
+	}
+	text := pokerStarsHand(hand)
+	if !gameType.HiLo() {
+		return text
+	}
+
+	low, err := gameType.EvaluateLow(hole, board)
+	if err != nil || !low.Qualified() {
+		return "HI: " + text
+	}
+	ranks := low.Strength.Ranks()
+	parts := make([]string, len(ranks))
+	for i, rank := range ranks {
+		parts[i] = rank.String()
+	}
+	return "HI: " + text + "; LO: " + strings.Join(parts, ",")
+}
+
+// pokerStarsHand 牌型在PokerStars中的写法
+func pokerStarsHand(hand poker.Hand) string {
+	ranks := hand.Ranks
+	if len(ranks) == 0 {
+		ranks = hand.Strength.Ranks()
+	}
+	if len(ranks) == 0 {
+		return strings.ToLower(hand.Type.EnglishName())
+	}
+
+	switch hand.Type {
+	case poker.HighCard:
+		return "high card " + ranks[0].EnglishName(false)
+	case poker.OnePair:
+		return "a pair of " + ranks[0].EnglishName(true)
+	case poker.TwoPair:
+		return fmt.Sprintf("two pair, %s and %s", ranks[0].EnglishName(true), ranks[1].EnglishName(true))
+	case poker.ThreeOfAKind:
+		return "three of a kind, " + ranks[0].EnglishName(true)
+	case poker.Straight:
+		return fmt.Sprintf("a straight, %s to %s", straightLow(hand.Cards, ranks[0]).EnglishName(false), ranks[0].EnglishName(false))
+	case poker.Flush:
+		return fmt.Sprintf("a flush, %s high", ranks[0].EnglishName(false))
+	case poker.FullHouse:
+		return fmt.Sprintf("a full house, %s full of %s", ranks[0].EnglishName(true), ranks[1].EnglishName(true))
+	case poker.FourOfAKind:
+		return "four of a kind, " + ranks[0].EnglishName(true)
+	case poker.StraightFlush:
+		return fmt.Sprintf("a straight flush, %s to %s", straightLow(hand.Cards, ranks[0]).EnglishName(false), ranks[0].EnglishName(false))
+	case poker.RoyalFlush:
+		return "a Royal Flush"
+	default:
+		return strings.ToLower(hand.Type.EnglishName())
+	}
+}
+
+// straightLow 顺子的最小牌，high为顺子的最大牌（A-2-3-4-5和短牌A-6-7-8-9中A作为最小牌）
+func straightLow(cards []poker.Card, high poker.Rank) poker.Rank {
+	low := poker.Ace
+	hasAce := false
+	for _, card := range cards {
+		if card.Rank == poker.Ace {
+			hasAce = true
+		}
+		low = min(low, card.Rank)
+	}
+	if hasAce && high != poker.Ace {
+		return poker.Ace
+	}
+	return low
+}
@@ -0,0 +1,240 @@
+// PokerStars牌局历史测试
+// 作用：用构造的牌局记录校验PokerStars文本的完整输出（加注、未被跟注的退还、摊牌和边池），只写出导出者本人的底牌，以及摊牌牌型的写法
+
+package room
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/statemachine"
+)
+
+// uncalledRecord 三人局：翻牌前alice加注、bob弃牌、carol跟注，翻牌圈alice下注后carol弃牌，40未被跟注
+func uncalledRecord(t *testing.T) *HandRecord {
+	return &HandRecord{
+		RoomName:   "测试桌",
+		GameType:   "texas_holdem",
+		SmallBlind: 5,
+		BigBlind:   10,
+		MaxPlayers: 6,
+		StartTime:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Players: []HandPlayer{
+			{ID: 1, Username: "alice", Position: 0, StartChips: 1000, Contributed: 70, Collected: 105, Cards: mustCards(t, "AsKs"), IsDealer: true},
+			{ID: 2, Username: "bob", Position: 1, StartChips: 500, Contributed: 5, Cards: mustCards(t, "9h9c"), IsSmallBlind: true, Folded: true},
+			{ID: 3, Username: "carol", Position: 2, StartChips: 800, Contributed: 30, Cards: mustCards(t, "QdQc"), IsBigBlind: true, Folded: true},
+		},
+		Actions: []HandAction{
+			{Street: "preflop", PlayerID: 2, Action: ActionPostSmallBlind, Amount: 5, To: 5},
+			{Street: "preflop", PlayerID: 3, Action: ActionPostBigBlind, Amount: 10, To: 10},
+			{Street: "preflop", PlayerID: 1, Action: statemachine.Raise.Key(), Amount: 30, To: 30},
+			{Street: "preflop", PlayerID: 2, Action: statemachine.Fold.Key()},
+			{Street: "preflop", PlayerID: 3, Action: statemachine.Call.Key(), Amount: 20, To: 30},
+			{Street: "flop", PlayerID: 3, Action: statemachine.Check.Key()},
+			{Street: "flop", PlayerID: 1, Action: statemachine.Bet.Key(), Amount: 40, To: 40},
+			{Street: "flop", PlayerID: 3, Action: statemachine.Fold.Key()},
+		},
+		Board: mustCards(t, "Ah7c2d"),
+		Pots: []PotResult{
+			{Index: 0, Amount: 105, Eligible: []int64{1}, Winners: []int64{1}, Shares: map[int64]int{1: 105}},
+		},
+		Pot: 105,
+	}
+}
+
+// sidePotRecord 三人局：bob翻牌前全押，carol和alice继续下注形成边池，河牌alice弃牌后bob与carol摊牌
+func sidePotRecord(t *testing.T) *HandRecord {
+	return &HandRecord{
+		RoomName:   "测试桌",
+		GameType:   "texas_holdem",
+		SmallBlind: 5,
+		BigBlind:   10,
+		MaxPlayers: 6,
+		StartTime:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Players: []HandPlayer{
+			{ID: 1, Username: "alice", Position: 0, StartChips: 1000, Contributed: 300, Cards: mustCards(t, "AhAd"), IsDealer: true, Folded: true},
+			{ID: 2, Username: "bob", Position: 1, StartChips: 100, Contributed: 100, Cards: mustCards(t, "KcKd"), IsSmallBlind: true},
+			{ID: 3, Username: "carol", Position: 2, StartChips: 1000, Contributed: 600, Collected: 1000, Cards: mustCards(t, "JsTs"), IsBigBlind: true},
+		},
+		Actions: []HandAction{
+			{Street: "preflop", PlayerID: 2, Action: ActionPostSmallBlind, Amount: 5, To: 5},
+			{Street: "preflop", PlayerID: 3, Action: ActionPostBigBlind, Amount: 10, To: 10},
+			{Street: "preflop", PlayerID: 1, Action: statemachine.Raise.Key(), Amount: 30, To: 30},
+			{Street: "preflop", PlayerID: 2, Action: statemachine.AllIn.Key(), Amount: 95, To: 100, AllIn: true},
+			{Street: "preflop", PlayerID: 3, Action: statemachine.Call.Key(), Amount: 90, To: 100},
+			{Street: "preflop", PlayerID: 1, Action: statemachine.Call.Key(), Amount: 70, To: 100},
+			{Street: "flop", PlayerID: 3, Action: statemachine.Bet.Key(), Amount: 200, To: 200},
+			{Street: "flop", PlayerID: 1, Action: statemachine.Call.Key(), Amount: 200, To: 200},
+			{Street: "turn", PlayerID: 3, Action: statemachine.Check.Key()},
+			{Street: "turn", PlayerID: 1, Action: statemachine.Check.Key()},
+			{Street: "river", PlayerID: 3, Action: statemachine.Bet.Key(), Amount: 300, To: 300},
+			{Street: "river", PlayerID: 1, Action: statemachine.Fold.Key()},
+		},
+		Board: mustCards(t, "2c7h9d3s8h"),
+		Pots: []PotResult{
+			{Index: 0, Amount: 300, Eligible: []int64{2, 3}, Winners: []int64{3}, Shares: map[int64]int{3: 300}},
+			{Index: 1, Amount: 700, Eligible: []int64{3}, Winners: []int64{3}, Shares: map[int64]int{3: 700}},
+		},
+		Pot:      1000,
+		Showdown: true,
+	}
+}
+
+func TestFormatPokerStars(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(t *testing.T) *HandRecord
+		hero   int64
+		want   []string
+	}{
+		{
+			name:   "翻牌圈下注未被跟注",
+			record: uncalledRecord,
+			hero:   1,
+			want: []string{
+				"PokerStars Hand #42:  Hold'em No Limit (5/10) - 2026/01/02 03:04:05 UTC",
+				"Table '测试桌' 6-max Seat #1 is the button",
+				"Seat 1: alice (1000 in chips)",
+				"Seat 2: bob (500 in chips)",
+				"Seat 3: carol (800 in chips)",
+				"bob: posts small blind 5",
+				"carol: posts big blind 10",
+				"*** HOLE CARDS ***",
+				"Dealt to alice [As Ks]",
+				"alice: raises 20 to 30",
+				"bob: folds",
+				"carol: calls 20",
+				"*** FLOP *** [Ah 7c 2d]",
+				"carol: checks",
+				"alice: bets 40",
+				"carol: folds",
+				"Uncalled bet (40) returned to alice",
+				"alice collected 65 from pot",
+				"*** SUMMARY ***",
+				"Total pot 65 | Rake 0",
+				"Board [Ah 7c 2d]",
+				"Seat 1: alice (button) collected (65)",
+				"Seat 2: bob (small blind) folded before Flop",
+				"Seat 3: carol (big blind) folded on the Flop",
+			},
+		},
+		{
+			name:   "全押形成边池后摊牌",
+			record: sidePotRecord,
+			hero:   1,
+			want: []string{
+				"PokerStars Hand #42:  Hold'em No Limit (5/10) - 2026/01/02 03:04:05 UTC",
+				"Table '测试桌' 6-max Seat #1 is the button",
+				"Seat 1: alice (1000 in chips)",
+				"Seat 2: bob (100 in chips)",
+				"Seat 3: carol (1000 in chips)",
+				"bob: posts small blind 5",
+				"carol: posts big blind 10",
+				"*** HOLE CARDS ***",
+				"Dealt to alice [Ah Ad]",
+				"alice: raises 20 to 30",
+				"bob: raises 70 to 100 and is all-in",
+				"carol: calls 90",
+				"alice: calls 70",
+				"*** FLOP *** [2c 7h 9d]",
+				"carol: bets 200",
+				"alice: calls 200",
+				"*** TURN *** [2c 7h 9d] [3s]",
+				"carol: checks",
+				"alice: checks",
+				"*** RIVER *** [2c 7h 9d 3s] [8h]",
+				"carol: bets 300",
+				"alice: folds",
+				"Uncalled bet (300) returned to carol",
+				"*** SHOW DOWN ***",
+				"bob: shows [Kc Kd] (a pair of Kings)",
+				"carol: shows [Js Ts] (a straight, Seven to Jack)",
+				"carol collected 400 from side pot",
+				"carol collected 300 from main pot",
+				"*** SUMMARY ***",
+				"Total pot 700 Main pot 300. Side pot 400. | Rake 0",
+				"Board [2c 7h 9d 3s 8h]",
+				"Seat 1: alice (button) folded on the River",
+				"Seat 2: bob (small blind) showed [Kc Kd] and lost with a pair of Kings",
+				"Seat 3: carol (big blind) showed [Js Ts] and won (700) with a straight, Seven to Jack",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatPokerStars(tt.record(t), 42, tt.hero)
+			want := strings.Join(tt.want, "\n") + "\n"
+			if got != want {
+				t.Errorf("FormatPokerStars 输出不一致\n实际:\n%s\n期望:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestFormatPokerStarsHoleCards(t *testing.T) {
+	tests := []struct {
+		name string
+		hero int64
+		want []string // 期望写出的 "Dealt to" 行
+	}{
+		{name: "只写出导出者的底牌", hero: 3, want: []string{"Dealt to carol [Qd Qc]"}},
+		{name: "弃牌的导出者同样写出底牌", hero: 2, want: []string{"Dealt to bob [9h 9c]"}},
+		{name: "导出者不在牌局中时不写出底牌", hero: 99},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, line := range strings.Split(FormatPokerStars(uncalledRecord(t), 42, tt.hero), "\n") {
+				if strings.HasPrefix(line, "Dealt to ") {
+					got = append(got, line)
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Dealt to 行 = %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPokerStarsHandText(t *testing.T) {
+	tests := []struct {
+		name     string
+		gameType poker.GameType
+		hole     string
+		board    string
+		want     string
+	}{
+		{name: "高牌", hole: "AsJd", board: "2c7h9d3sKh", want: "high card Ace"},
+		{name: "两对", hole: "KsKd", board: "7c7h2d3s9h", want: "two pair, Kings and Sevens"},
+		{name: "A到5的顺子", hole: "As2d", board: "3c4h5dKsQh", want: "a straight, Ace to Five"},
+		{name: "同花", hole: "Ah9h", board: "2h5h7hKsQd", want: "a flush, Ace high"},
+		{name: "葫芦", hole: "KsKd", board: "Kc7h7d2s3h", want: "a full house, Kings full of Sevens"},
+		{name: "皇家同花顺", hole: "AsKs", board: "QsJsTs2d3h", want: "a Royal Flush"},
+		{
+			name:     "高低牌附上低牌",
+			gameType: poker.OmahaHiLo,
+			hole:     "As2d7c8c",
+			board:    "3h4s5dKcQh",
+			want:     "HI: a straight, Ace to Five; LO: 5,4,3,2,A",
+		},
+		{
+			name:     "高低牌没有合格低牌",
+			gameType: poker.OmahaHiLo,
+			hole:     "AsKdQcJc",
+			board:    "Th9s9dKcJh",
+			want:     "HI: a straight, Ten to Ace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pokerStarsHandText(tt.gameType, mustCards(t, tt.hole), mustCards(t, tt.board)); got != tt.want {
+				t.Errorf("pokerStarsHandText = %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
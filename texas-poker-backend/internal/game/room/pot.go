@@ -0,0 +1,264 @@
+// 底池结算
+// 作用：根据每位玩家本局的总投入构建主池和边池，按牌力分配每个底池，支持平分、零头分配和高低牌拆分
+
+package room
+
+import (
+	"sort"
+
+	"texas-poker-backend/internal/game/poker"
+)
+
+// Pot 底池（主池或边池）
+type Pot struct {
+	Amount   int     `json:"amount"`
+	Eligible []int64 `json:"eligible"` // 有资格赢取该底池的玩家（未弃牌且投入达到该层级）
+}
+
+// PotResult 单个底池的结算结果
+type PotResult struct {
+	Index    int           `json:"index"` // 0为主池，之后依次为边池
+	Amount   int           `json:"amount"`
+	Eligible []int64       `json:"eligible"`
+	Winners  []int64       `json:"winners"`
+	Shares   map[int64]int `json:"shares"`              // 每位获胜者分得的筹码
+	HandType string        `json:"hand_type,omitempty"` // 获胜牌型（无需比牌时为空）
+
+	// 高低牌玩法中有合格低牌时分别记录两半的赢家，Winners为两者的并集
+	HighWinners []int64 `json:"high_winners,omitempty"`
+	LowWinners  []int64 `json:"low_winners,omitempty"`
+	LowHand     string  `json:"low_hand,omitempty"` // 获胜的低牌（如 8-6-4-2-A）
+}
+
+// BuildPots 根据每位玩家的总投入构建主池和边池
+// 弃牌玩家的投入计入底池但不具备赢取资格；只有一人有资格的底池即为未被跟注的退还部分
+func BuildPots(contributions map[int64]int, folded map[int64]bool) []Pot {
+	// 以仍在牌局中的玩家投入作为分层依据
+	levelSet := make(map[int]bool)
+	for playerID, amount := range contributions {
+		if amount > 0 && !folded[playerID] {
+			levelSet[amount] = true
+		}
+	}
+
+	if len(levelSet) == 0 {
+		return nil
+	}
+
+	levels := make([]int, 0, len(levelSet))
+	for level := range levelSet {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+
+	playerIDs := make([]int64, 0, len(contributions))
+	for playerID := range contributions {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Slice(playerIDs, func(i, j int) bool { return playerIDs[i] < playerIDs[j] })
+
+	pots := make([]Pot, 0, len(levels))
+	previous := 0
+	for i, level := range levels {
+		pot := Pot{}
+		for _, playerID := range playerIDs {
+			amount := contributions[playerID]
+			// 最高层级吸收所有剩余投入（例如弃牌玩家超出的部分）
+			if i == len(levels)-1 {
+				pot.Amount += max(amount-previous, 0)
+			} else {
+				pot.Amount += max(min(amount, level)-previous, 0)
+			}
+			if !folded[playerID] && amount >= level {
+				pot.Eligible = append(pot.Eligible, playerID)
+			}
+		}
+		previous = level
+
+		// 资格相同的相邻底池合并
+		if n := len(pots); n > 0 && sameEligible(pots[n-1].Eligible, pot.Eligible) {
+			pots[n-1].Amount += pot.Amount
+			continue
+		}
+		if pot.Amount > 0 {
+			pots = append(pots, pot)
+		}
+	}
+
+	return pots
+}
+
+// AwardPots 将每个底池分配给有资格玩家中牌力最强者，牌力相同则平分
+// seatOrder为从庄家左手开始的座位顺序，无法平分的零头按该顺序逐个分配
+func AwardPots(pots []Pot, hands map[int64]poker.Hand, seatOrder []int64) []PotResult {
+	results := make([]PotResult, 0, len(pots))
+
+	for i, pot := range pots {
+		result := PotResult{
+			Index:    i,
+			Amount:   pot.Amount,
+			Eligible: pot.Eligible,
+			Shares:   make(map[int64]int),
+		}
+
+		winners := bestHands(pot.Eligible, hands)
+		if len(pot.Eligible) > 1 && len(winners) > 0 {
+			result.HandType = hands[winners[0]].Type.String()
+		}
+		if len(winners) == 0 {
+			// 无法比较牌力时由所有有资格的玩家平分
+			winners = pot.Eligible
+		}
+
+		result.Winners = orderBySeat(winners, seatOrder)
+		share := pot.Amount / len(result.Winners)
+		remainder := pot.Amount % len(result.Winners)
+		for j, winnerID := range result.Winners {
+			result.Shares[winnerID] = share
+			if j < remainder {
+				result.Shares[winnerID]++
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// AwardHiLoPots 高低牌玩法的底池分配：每个底池由牌力最强者和最好的合格低牌各得一半
+// 没有合格低牌时高牌独得；无法平分的一个筹码归高牌一半，每一半的零头按seatOrder逐个分配
+func AwardHiLoPots(pots []Pot, hands map[int64]poker.Hand, lows map[int64]poker.LowHand, seatOrder []int64) []PotResult {
+	results := make([]PotResult, 0, len(pots))
+
+	for i, pot := range pots {
+		result := PotResult{
+			Index:    i,
+			Amount:   pot.Amount,
+			Eligible: pot.Eligible,
+			Shares:   make(map[int64]int),
+		}
+
+		high := bestHands(pot.Eligible, hands)
+		if len(pot.Eligible) > 1 && len(high) > 0 {
+			result.HandType = hands[high[0]].Type.String()
+		}
+		if len(high) == 0 {
+			high = pot.Eligible
+		}
+		high = orderBySeat(high, seatOrder)
+
+		// 只有一人有资格的底池（未被跟注的部分）不拆分
+		var low []int64
+		if len(pot.Eligible) > 1 {
+			low = orderBySeat(bestLows(pot.Eligible, lows), seatOrder)
+		}
+
+		highShares, lowShares := poker.SplitHiLo(pot.Amount, len(high), len(low))
+		winners := make([]int64, 0, len(high)+len(low))
+		for j, winnerID := range high {
+			result.Shares[winnerID] += highShares[j]
+			winners = append(winners, winnerID)
+		}
+		for j, winnerID := range low {
+			if _, exists := result.Shares[winnerID]; !exists {
+				winners = append(winners, winnerID)
+			}
+			result.Shares[winnerID] += lowShares[j]
+		}
+		result.Winners = orderBySeat(winners, seatOrder)
+
+		if len(low) > 0 {
+			result.HighWinners = high
+			result.LowWinners = low
+			result.LowHand = lows[low[0]].String()
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// bestLows 找出有资格玩家中最好的合格低牌（可能多人并列，没有合格低牌时为空）
+func bestLows(eligible []int64, lows map[int64]poker.LowHand) []int64 {
+	var winners []int64
+	var best poker.LowStrength
+	for _, playerID := range eligible {
+		low, ok := lows[playerID]
+		if !ok || !low.Qualified() {
+			continue
+		}
+		switch {
+		case low.Strength > best:
+			winners = []int64{playerID}
+			best = low.Strength
+		case low.Strength == best:
+			winners = append(winners, playerID)
+		}
+	}
+	return winners
+}
+
+// bestHands 找出有资格玩家中牌力最强的玩家（可能多人并列）
+func bestHands(eligible []int64, hands map[int64]poker.Hand) []int64 {
+	if len(eligible) == 1 {
+		return eligible
+	}
+
+	var winners []int64
+	var best poker.Hand
+	for _, playerID := range eligible {
+		hand, ok := hands[playerID]
+		if !ok {
+			continue
+		}
+		switch {
+		case len(winners) == 0:
+			winners = []int64{playerID}
+			best = hand
+		case poker.CompareHands(hand, best) > 0:
+			winners = []int64{playerID}
+			best = hand
+		case poker.CompareHands(hand, best) == 0:
+			winners = append(winners, playerID)
+		}
+	}
+	return winners
+}
+
+// orderBySeat 按座位顺序排列玩家，不在座位顺序中的玩家排在最后
+func orderBySeat(playerIDs []int64, seatOrder []int64) []int64 {
+	rank := make(map[int64]int, len(seatOrder))
+	for i, playerID := range seatOrder {
+		rank[playerID] = i
+	}
+
+	ordered := make([]int64, len(playerIDs))
+	copy(ordered, playerIDs)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ri, ok := rank[ordered[i]]
+		if !ok {
+			ri = len(seatOrder)
+		}
+		rj, ok := rank[ordered[j]]
+		if !ok {
+			rj = len(seatOrder)
+		}
+		return ri < rj
+	})
+	return ordered
+}
+
+// sameEligible 检查两个资格列表是否相同
+func sameEligible(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
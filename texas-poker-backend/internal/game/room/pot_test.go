@@ -0,0 +1,250 @@
+// 底池结算测试
+// 作用：校验主池/边池的构建、按座位顺序分配零头的底池结算，以及高低牌玩法的通吃和四分之一底池
+
+package room
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"texas-poker-backend/internal/game/poker"
+)
+
+// mustCards 解析连续书写的牌面记法（如 "AsKd"，每张牌两个字符）
+func mustCards(t *testing.T, notation string) []poker.Card {
+	t.Helper()
+	cards := make([]poker.Card, 0, len(notation)/2)
+	for i := 0; i+1 < len(notation); i += 2 {
+		card, err := poker.ParseCard(strings.ToUpper(notation[i : i+2]))
+		if err != nil {
+			t.Fatalf("ParseCard(%q): %v", notation[i:i+2], err)
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// mustHand 评估用牌面记法给出的手牌（如 "AsKdQcJh9s"）
+func mustHand(t *testing.T, notation string) poker.Hand {
+	t.Helper()
+	hand, err := poker.EvaluateHand(mustCards(t, notation))
+	if err != nil {
+		t.Fatalf("EvaluateHand(%q): %v", notation, err)
+	}
+	return hand
+}
+
+func TestBuildPots(t *testing.T) {
+	tests := []struct {
+		name          string
+		contributions map[int64]int
+		folded        map[int64]bool
+		want          []Pot
+	}{
+		{
+			name:          "投入相同只有主池",
+			contributions: map[int64]int{1: 100, 2: 100, 3: 100},
+			want:          []Pot{{Amount: 300, Eligible: []int64{1, 2, 3}}},
+		},
+		{
+			name:          "短码全押形成边池",
+			contributions: map[int64]int{1: 50, 2: 100, 3: 100},
+			want: []Pot{
+				{Amount: 150, Eligible: []int64{1, 2, 3}},
+				{Amount: 100, Eligible: []int64{2, 3}},
+			},
+		},
+		{
+			name:          "多个全押形成多个边池",
+			contributions: map[int64]int{1: 30, 2: 60, 3: 100, 4: 100},
+			want: []Pot{
+				{Amount: 120, Eligible: []int64{1, 2, 3, 4}},
+				{Amount: 90, Eligible: []int64{2, 3, 4}},
+				{Amount: 80, Eligible: []int64{3, 4}},
+			},
+		},
+		{
+			name:          "弃牌玩家的投入计入底池但没有资格",
+			contributions: map[int64]int{1: 50, 2: 200, 3: 120},
+			folded:        map[int64]bool{3: true},
+			want: []Pot{
+				{Amount: 150, Eligible: []int64{1, 2}},
+				{Amount: 220, Eligible: []int64{2}},
+			},
+		},
+		{
+			name:          "只剩一人时全部投入归为一个底池",
+			contributions: map[int64]int{1: 10, 2: 40, 3: 20},
+			folded:        map[int64]bool{1: true, 3: true},
+			want:          []Pot{{Amount: 70, Eligible: []int64{2}}},
+		},
+		{
+			name:          "没有投入",
+			contributions: map[int64]int{1: 0, 2: 0},
+			want:          nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildPots(tt.contributions, tt.folded)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildPots() = %+v，期望 %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAwardPots(t *testing.T) {
+	tests := []struct {
+		name      string
+		pots      []Pot
+		hands     map[int64]string
+		seatOrder []int64
+		want      []map[int64]int
+	}{
+		{
+			name:      "牌力最强者独得",
+			pots:      []Pot{{Amount: 300, Eligible: []int64{1, 2, 3}}},
+			hands:     map[int64]string{1: "AsAdKcQh9s", 2: "KsKdAcQd9c", 3: "2s3d4c5h7s"},
+			seatOrder: []int64{1, 2, 3},
+			want:      []map[int64]int{{1: 300}},
+		},
+		{
+			name:      "平分时零头给庄家左手最近的赢家",
+			pots:      []Pot{{Amount: 25, Eligible: []int64{1, 2, 3}}},
+			hands:     map[int64]string{1: "AsKdQcJh9s", 2: "AdKcQhJs9d", 3: "2s3d4c5h7s"},
+			seatOrder: []int64{3, 2, 1},
+			want:      []map[int64]int{{2: 13, 1: 12}},
+		},
+		{
+			name:      "三人平分按座位顺序分配两个零头",
+			pots:      []Pot{{Amount: 101, Eligible: []int64{1, 2, 3}}},
+			hands:     map[int64]string{1: "AsKdQcJh9s", 2: "AdKcQhJs9d", 3: "AhKsQdJc9h"},
+			seatOrder: []int64{3, 1, 2},
+			want:      []map[int64]int{{3: 34, 1: 34, 2: 33}},
+		},
+		{
+			name: "主池和边池分别结算",
+			pots: []Pot{
+				{Amount: 150, Eligible: []int64{1, 2, 3}},
+				{Amount: 100, Eligible: []int64{2, 3}},
+			},
+			hands:     map[int64]string{1: "AsAdAcQh9s", 2: "KsKdAhQd9c", 3: "2s3d4c5h7s"},
+			seatOrder: []int64{1, 2, 3},
+			want:      []map[int64]int{{1: 150}, {2: 100}},
+		},
+		{
+			name:      "只有一人有资格的底池直接退还",
+			pots:      []Pot{{Amount: 60, Eligible: []int64{2}}},
+			hands:     map[int64]string{},
+			seatOrder: []int64{1, 2},
+			want:      []map[int64]int{{2: 60}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hands := make(map[int64]poker.Hand, len(tt.hands))
+			for playerID, notation := range tt.hands {
+				hands[playerID] = mustHand(t, notation)
+			}
+
+			results := AwardPots(tt.pots, hands, tt.seatOrder)
+			if len(results) != len(tt.want) {
+				t.Fatalf("结算了%d个底池，期望%d个", len(results), len(tt.want))
+			}
+			for i, result := range results {
+				if !reflect.DeepEqual(result.Shares, tt.want[i]) {
+					t.Errorf("底池%d的分配为%v，期望%v", i, result.Shares, tt.want[i])
+				}
+				if want := orderBySeat(result.Winners, tt.seatOrder); !reflect.DeepEqual(result.Winners, want) {
+					t.Errorf("底池%d的赢家%v没有按座位顺序排列", i, result.Winners)
+				}
+			}
+		})
+	}
+}
+
+func TestAwardHiLoPots(t *testing.T) {
+	tests := []struct {
+		name      string
+		board     string
+		holes     map[int64]string
+		pots      []Pot
+		seatOrder []int64
+		want      []map[int64]int
+		wantLow   []int64 // 主池的低牌赢家（nil表示没有合格低牌）
+	}{
+		{
+			name:      "同时拿到最大高牌和最好低牌时通吃",
+			board:     "3h4d5cKdKs",
+			holes:     map[int64]string{1: "As2sQcJc", 2: "QhQdJhTd"},
+			pots:      []Pot{{Amount: 101, Eligible: []int64{1, 2}}},
+			seatOrder: []int64{2, 1},
+			want:      []map[int64]int{{1: 101}},
+			wantLow:   []int64{1},
+		},
+		{
+			name:      "没有合格低牌时高牌独得",
+			board:     "9hTdJcKdKs",
+			holes:     map[int64]string{1: "AsQsQc2c", 2: "2d3d4d5d"},
+			pots:      []Pot{{Amount: 101, Eligible: []int64{1, 2}}},
+			seatOrder: []int64{1, 2},
+			want:      []map[int64]int{{1: 101}},
+		},
+		{
+			name:  "低牌平分得到四分之一，零头按座位顺序",
+			board: "3h4d8cKdKs",
+			holes: map[int64]string{1: "As2sQcJc", 2: "Ad2dQhJh", 3: "KhKc9s9d"},
+			pots: []Pot{
+				{Amount: 103, Eligible: []int64{1, 2, 3}},
+				{Amount: 40, Eligible: []int64{3}},
+			},
+			seatOrder: []int64{2, 3, 1},
+			want:      []map[int64]int{{3: 52, 2: 26, 1: 25}, {3: 40}},
+			wantLow:   []int64{2, 1},
+		},
+		{
+			name:      "高牌和低牌都平分时奇数筹码归高牌一半",
+			board:     "3h4d8cKdKs",
+			holes:     map[int64]string{1: "As2sQcJc", 2: "Ad2dQhJh"},
+			pots:      []Pot{{Amount: 101, Eligible: []int64{1, 2}}},
+			seatOrder: []int64{1, 2},
+			want:      []map[int64]int{{1: 51, 2: 50}},
+			wantLow:   []int64{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := mustCards(t, tt.board)
+			hands := make(map[int64]poker.Hand, len(tt.holes))
+			lows := make(map[int64]poker.LowHand, len(tt.holes))
+			for playerID, notation := range tt.holes {
+				hole := mustCards(t, notation)
+				var err error
+				if hands[playerID], err = poker.OmahaHiLo.Evaluate(hole, board); err != nil {
+					t.Fatalf("Evaluate(%q): %v", notation, err)
+				}
+				if lows[playerID], err = poker.OmahaHiLo.EvaluateLow(hole, board); err != nil {
+					t.Fatalf("EvaluateLow(%q): %v", notation, err)
+				}
+			}
+
+			results := AwardHiLoPots(tt.pots, hands, lows, tt.seatOrder)
+			if len(results) != len(tt.want) {
+				t.Fatalf("结算了%d个底池，期望%d个", len(results), len(tt.want))
+			}
+			for i, result := range results {
+				if !reflect.DeepEqual(result.Shares, tt.want[i]) {
+					t.Errorf("底池%d的分配为%v，期望%v", i, result.Shares, tt.want[i])
+				}
+			}
+			if !reflect.DeepEqual(results[0].LowWinners, tt.wantLow) {
+				t.Errorf("低牌赢家为%v，期望%v", results[0].LowWinners, tt.wantLow)
+			}
+		})
+	}
+}
@@ -0,0 +1,430 @@
+// OHH牌局重放
+// 作用：按OHH记录的座位、已知的牌和每一次操作，用房间引擎重新打一遍牌局，生成牌局记录并与OHH中的底池结算核对
+
+package room
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/statemachine"
+)
+
+// maxOHHTableSize 重放支持的最大桌子人数（座位号不能超过它）
+const maxOHHTableSize = 10
+
+// replayDeal 重放时已知的牌：亮出的底牌和公共牌，其余位置用牌堆中没有出现的牌补齐
+type replayDeal struct {
+	holeCards map[int64][]poker.Card
+	board     []poker.Card
+}
+
+// deck 按引擎的发牌顺序排好牌堆：从庄家左手开始每人每轮一张，之后每条街先烧一张牌再发公共牌
+func (d *replayDeal) deck(gameType poker.GameType, dealOrder []int64) *poker.Deck {
+	used := make(map[poker.Card]bool)
+	for _, cards := range d.holeCards {
+		for _, card := range cards {
+			used[card] = true
+		}
+	}
+	for _, card := range d.board {
+		used[card] = true
+	}
+
+	var filler []poker.Card
+	for _, card := range gameType.DeckType().Cards() {
+		if !used[card] {
+			filler = append(filler, card)
+		}
+	}
+	take := func(known []poker.Card, i int) poker.Card {
+		if i < len(known) {
+			return known[i]
+		}
+		card := filler[0]
+		filler = filler[1:]
+		return card
+	}
+
+	cards := make([]poker.Card, 0, gameType.DeckType().Size())
+	for round := 0; round < gameType.HoleCards(); round++ {
+		for _, playerID := range dealOrder {
+			cards = append(cards, take(d.holeCards[playerID], round))
+		}
+	}
+	dealt := 0
+	for _, count := range []int{3, 1, 1} {
+		cards = append(cards, take(nil, 0)) // 烧牌
+		for i := 0; i < count; i++ {
+			cards = append(cards, take(d.board, dealt))
+			dealt++
+		}
+	}
+	return poker.NewStackedDeck(append(cards, filler...))
+}
+
+// ReplayOHH 用房间引擎重放一局OHH牌局，返回引擎生成的牌局记录和与OHH结算不一致之处（为空表示结算一致）
+// 金额含小数（如现金局的0.25）时按分换算为筹码；OHH中的玩家ID在记录中保持不变
+func ReplayOHH(hand *OHHHand) (*HandRecord, []string, error) {
+	gameType, err := hand.gameType()
+	if err != nil {
+		return nil, nil, err
+	}
+	if hand.AnteAmount > 0 {
+		return nil, nil, fmt.Errorf("不支持前注")
+	}
+
+	scale := hand.chipScale()
+	chips := func(amount float64) int {
+		return int(math.Round(amount * scale))
+	}
+
+	players := make([]OHHPlayer, 0, len(hand.Players))
+	for _, player := range hand.Players {
+		if !player.IsSittingOut && player.StartingStack > 0 {
+			players = append(players, player)
+		}
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].Seat < players[j].Seat })
+	if len(players) < 2 {
+		return nil, nil, fmt.Errorf("至少需要2名参与牌局的玩家")
+	}
+	if need := len(players)*gameType.HoleCards() + 8; need > gameType.DeckType().Size() {
+		return nil, nil, fmt.Errorf("玩家过多，%s不够发牌", gameType.DeckType())
+	}
+
+	if hand.TableSize > maxOHHTableSize || players[len(players)-1].Seat > maxOHHTableSize {
+		return nil, nil, fmt.Errorf("最多支持%d人桌", maxOHHTableSize)
+	}
+
+	// 引擎中的玩家ID按座位顺序从1开始编号，结束后换回OHH中的ID
+	tableSize := max(hand.TableSize, players[len(players)-1].Seat)
+	room := NewRoom(0, hand.TableName, "", 0, chips(hand.SmallBlindAmount), chips(hand.BigBlindAmount), tableSize, false)
+	room.GameType = gameType
+	engineIDs := make(map[int64]int64, len(players))
+	ohhIDs := make(map[int64]int64, len(players))
+	seats := make(map[int]bool, len(players))
+	for i, player := range players {
+		if player.Seat < 1 || seats[player.Seat] {
+			return nil, nil, fmt.Errorf("玩家%s的座位号%d无效", player.Name, player.Seat)
+		}
+		if _, exists := engineIDs[player.ID]; exists {
+			return nil, nil, fmt.Errorf("玩家ID %d重复", player.ID)
+		}
+		seats[player.Seat] = true
+
+		engineID := int64(i + 1)
+		if err := room.AddPlayer(engineID, player.Name, chips(player.StartingStack)); err != nil {
+			return nil, nil, err
+		}
+		room.Players[engineID].Position = player.Seat - 1
+		engineIDs[player.ID] = engineID
+		ohhIDs[engineID] = player.ID
+	}
+	room.DealerPosition = hand.DealerSeat - 2
+
+	deal, err := hand.knownCards(gameType, engineIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	room.replay = deal
+
+	var record *HandRecord
+	room.SetEventHandler(func(event Event) {
+		if event.Type == EventHandEnded {
+			record = event.Hand
+		}
+	})
+	defer func() {
+		room.mu.Lock()
+		room.stopTurnTimer()
+		room.mu.Unlock()
+	}()
+
+	if err := room.StartGame(); err != nil {
+		return nil, nil, err
+	}
+	blinds := make(map[string]HandAction)
+	room.mu.RLock()
+	for _, action := range room.CurrentGame.Actions {
+		blinds[action.Action] = action
+	}
+	room.mu.RUnlock()
+
+	for _, action := range hand.actions() {
+		if action.Action == OHHDealtCards || action.Action == OHHShowsCards || action.Action == OHHMucksCards {
+			continue
+		}
+		engineID, known := engineIDs[action.PlayerID]
+		if !known {
+			return nil, nil, fmt.Errorf("第%d个操作的玩家%d不在牌局中", action.ActionNumber, action.PlayerID)
+		}
+
+		var playerAction statemachine.PlayerAction
+		switch action.Action {
+		case OHHPostSB, OHHPostBB:
+			blind := blinds[ActionPostSmallBlind]
+			if action.Action == OHHPostBB {
+				blind = blinds[ActionPostBigBlind]
+			}
+			if blind.PlayerID != engineID || blind.Amount != chips(action.Amount) {
+				return nil, nil, fmt.Errorf("第%d个操作的盲注与引擎不一致（庄家座位%d）", action.ActionNumber, hand.DealerSeat)
+			}
+			continue
+		case OHHFold:
+			playerAction = statemachine.Fold
+		case OHHCheck:
+			playerAction = statemachine.Check
+		case OHHCall:
+			playerAction = statemachine.Call
+		case OHHBet:
+			playerAction = statemachine.Bet
+		case OHHRaise:
+			playerAction = statemachine.Raise
+		default:
+			return nil, nil, fmt.Errorf("第%d个操作%q不支持重放", action.ActionNumber, action.Action)
+		}
+		if record != nil {
+			return nil, nil, fmt.Errorf("牌局已经结束，第%d个操作多余", action.ActionNumber)
+		}
+
+		room.mu.RLock()
+		current, bet := int64(0), 0
+		if room.BettingRound != nil {
+			current = room.BettingRound.GetCurrentPlayer()
+			bet = room.BettingRound.GetPlayerBets()[engineID]
+		}
+		room.mu.RUnlock()
+		if current != engineID {
+			return nil, nil, fmt.Errorf("第%d个操作不是玩家%d的轮次", action.ActionNumber, action.PlayerID)
+		}
+
+		// 加注的金额是本次投入的筹码，引擎需要加注到的总下注；下注和加注全押时按全押处理
+		amount := chips(action.Amount)
+		if playerAction == statemachine.Raise {
+			amount += bet
+		}
+		if action.IsAllIn && (playerAction == statemachine.Bet || playerAction == statemachine.Raise) {
+			playerAction = statemachine.AllIn
+		}
+
+		result, err := room.ProcessPlayerAction(engineID, playerAction, amount)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !result.Success {
+			return nil, nil, fmt.Errorf("第%d个操作无效: %s", action.ActionNumber, result.Message)
+		}
+		if result.Amount != chips(action.Amount) {
+			return nil, nil, fmt.Errorf("第%d个操作投入%d，引擎为%d", action.ActionNumber, chips(action.Amount), result.Amount)
+		}
+	}
+	if record == nil {
+		return nil, nil, fmt.Errorf("操作不完整，重放后牌局没有结束")
+	}
+
+	mismatches := hand.compareSettlement(record, deal, engineIDs, chips)
+	record.remapPlayers(ohhIDs)
+	record.GameID = "ohh_" + hand.GameNumber
+	record.RoomName = hand.TableName
+	record.StartTime = hand.StartDateUTC
+	record.EndTime = hand.StartDateUTC
+	return record, mismatches, nil
+}
+
+// gameType OHH的游戏类型和限注类型对应的玩法
+func (h *OHHHand) gameType() (poker.GameType, error) {
+	switch {
+	case h.GameType == "Holdem" && h.BetLimit.BetType == "NL":
+		if h.Variant != "" {
+			return poker.ParseGameType(h.Variant)
+		}
+		return poker.TexasHoldem, nil
+	case h.GameType == "Omaha" && h.BetLimit.BetType == "PL":
+		return poker.PotLimitOmaha, nil
+	case h.GameType == "OmahaHiLo" && h.BetLimit.BetType == "PL":
+		return poker.OmahaHiLo, nil
+	default:
+		return "", fmt.Errorf("不支持的玩法: %s %s", h.GameType, h.BetLimit.BetType)
+	}
+}
+
+// chipScale 金额到筹码的换算比例：全部为整数时为1，否则按分换算
+func (h *OHHHand) chipScale() float64 {
+	amounts := []float64{h.SmallBlindAmount, h.BigBlindAmount}
+	for _, player := range h.Players {
+		amounts = append(amounts, player.StartingStack)
+	}
+	for _, action := range h.actions() {
+		amounts = append(amounts, action.Amount)
+	}
+	for _, pot := range h.Pots {
+		for _, win := range pot.PlayerWins {
+			amounts = append(amounts, win.WinAmount)
+		}
+	}
+
+	for _, amount := range amounts {
+		if amount != math.Trunc(amount) {
+			return 100
+		}
+	}
+	return 1
+}
+
+// actions 按操作编号排列的全部操作
+func (h *OHHHand) actions() []OHHAction {
+	var actions []OHHAction
+	for _, round := range h.Rounds {
+		actions = append(actions, round.Actions...)
+	}
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].ActionNumber < actions[j].ActionNumber })
+	return actions
+}
+
+// knownCards 收集OHH中出现的底牌和公共牌，检查它们属于该玩法的牌堆且没有重复
+func (h *OHHHand) knownCards(gameType poker.GameType, engineIDs map[int64]int64) (*replayDeal, error) {
+	deal := &replayDeal{holeCards: make(map[int64][]poker.Card)}
+	for _, street := range []string{OHHFlop, OHHTurn, OHHRiver} {
+		for _, round := range h.Rounds {
+			if round.Street == street {
+				deal.board = append(deal.board, round.Cards...)
+			}
+		}
+	}
+	if len(deal.board) > 5 {
+		return nil, fmt.Errorf("公共牌超过5张")
+	}
+
+	for _, action := range h.actions() {
+		engineID, known := engineIDs[action.PlayerID]
+		if len(action.Cards) == 0 || !known {
+			continue
+		}
+		if len(deal.holeCards[engineID]) < len(action.Cards) {
+			deal.holeCards[engineID] = action.Cards
+		}
+	}
+
+	seen := make(map[poker.Card]bool)
+	check := func(card poker.Card) error {
+		if !gameType.DeckType().Contains(card) {
+			return fmt.Errorf("%s中没有%s", gameType.DeckType(), card)
+		}
+		if seen[card] {
+			return fmt.Errorf("%s出现了不止一次", card)
+		}
+		seen[card] = true
+		return nil
+	}
+	for _, card := range deal.board {
+		if err := check(card); err != nil {
+			return nil, err
+		}
+	}
+	for _, cards := range deal.holeCards {
+		if len(cards) > gameType.HoleCards() {
+			return nil, fmt.Errorf("底牌应为%d张，实际为%d张", gameType.HoleCards(), len(cards))
+		}
+		for _, card := range cards {
+			if err := check(card); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return deal, nil
+}
+
+// compareSettlement 核对引擎的结算与OHH中记录的每位玩家赢得的筹码（不含未被跟注而退还的部分）
+func (h *OHHHand) compareSettlement(record *HandRecord, deal *replayDeal, engineIDs map[int64]int64, chips func(float64) int) []string {
+	mismatches := make([]string, 0)
+	if len(record.Board) != len(deal.board) {
+		mismatches = append(mismatches, fmt.Sprintf("公共牌应为%d张，引擎发了%d张", len(deal.board), len(record.Board)))
+	}
+
+	expected := make(map[int64]int)
+	for _, pot := range h.Pots {
+		for _, win := range pot.PlayerWins {
+			expected[engineIDs[win.PlayerID]] += chips(win.WinAmount)
+		}
+	}
+
+	uncalledID, uncalled := uncalledBet(record.Players)
+	for _, player := range record.Players {
+		if record.Showdown && !player.Folded && len(deal.holeCards[player.ID]) < len(player.Cards) {
+			mismatches = append(mismatches, fmt.Sprintf("玩家%s进入摊牌但没有亮出底牌", player.Username))
+		}
+
+		collected := player.Collected
+		if player.ID == uncalledID {
+			collected -= uncalled
+		}
+		if collected != expected[player.ID] {
+			mismatches = append(mismatches, fmt.Sprintf("玩家%s应赢得%d，引擎结算为%d", player.Username, expected[player.ID], collected))
+		}
+	}
+	return mismatches
+}
+
+// LinkUsers 将导入牌局中按用户名匹配到的玩家换为对应的用户ID，返回匹配到的玩家
+// 未匹配的玩家换为负数ID，避免OHH中的玩家ID与真实用户的ID冲突（否则导出时可能把别人的底牌当作该用户的）；
+// 同名的多个玩家无法确定哪一个是该用户，都不关联
+func (record *HandRecord) LinkUsers(userIDs map[string]int64) []HandPlayer {
+	names := make(map[string]int, len(record.Players))
+	for _, player := range record.Players {
+		names[player.Username]++
+	}
+
+	ids := make(map[int64]int64, len(record.Players))
+	for i, player := range record.Players {
+		if userID, ok := userIDs[player.Username]; ok && names[player.Username] == 1 {
+			ids[player.ID] = userID
+		} else {
+			ids[player.ID] = -int64(i + 1)
+		}
+	}
+	record.remapPlayers(ids)
+
+	linked := make([]HandPlayer, 0, len(record.Players))
+	for _, player := range record.Players {
+		if player.ID > 0 {
+			linked = append(linked, player)
+		}
+	}
+	return linked
+}
+
+// remapPlayers 将记录中的玩家ID换为ids中对应的ID
+func (record *HandRecord) remapPlayers(ids map[int64]int64) {
+	remap := func(playerIDs []int64) []int64 {
+		mapped := make([]int64, len(playerIDs))
+		for i, playerID := range playerIDs {
+			mapped[i] = ids[playerID]
+		}
+		return mapped
+	}
+
+	for i := range record.Players {
+		record.Players[i].ID = ids[record.Players[i].ID]
+	}
+	for i := range record.Actions {
+		record.Actions[i].PlayerID = ids[record.Actions[i].PlayerID]
+	}
+	for i := range record.Pots {
+		pot := &record.Pots[i]
+		pot.Eligible = remap(pot.Eligible)
+		pot.Winners = remap(pot.Winners)
+		pot.HighWinners = remap(pot.HighWinners)
+		pot.LowWinners = remap(pot.LowWinners)
+		shares := make(map[int64]int, len(pot.Shares))
+		for playerID, share := range pot.Shares {
+			shares[ids[playerID]] = share
+		}
+		pot.Shares = shares
+	}
+	if record.WinnerID != 0 {
+		record.WinnerID = ids[record.WinnerID]
+	}
+}
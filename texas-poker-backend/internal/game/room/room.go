@@ -5,10 +5,13 @@ package room
 
 import (
 	"fmt"
+	"log"
+	"sort"
 	"sync"
 	"time"
 
 	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/poker/equity"
 	"texas-poker-backend/internal/game/statemachine"
 )
 
@@ -30,10 +33,14 @@ type Player struct {
 	Status   PlayerStatus      `json:"status"`
 	Cards    []poker.Card      `json:"cards,omitempty"` // 手牌（只有本人可见）
 	LastAction statemachine.PlayerAction `json:"last_action,omitempty"`
-	BetAmount int               `json:"bet_amount"` // 本局已下注金额
+	HasActed bool              `json:"has_acted"` // 本局是否已经操作过（区分弃牌与未操作）
+	BetAmount int               `json:"bet_amount"` // 当前下注轮已下注金额
 	IsDealer bool              `json:"is_dealer"`  // 是否是庄家
 	IsSmallBlind bool          `json:"is_small_blind"` // 是否是小盲注
 	IsBigBlind bool            `json:"is_big_blind"`   // 是否是大盲注
+	TimeBank time.Duration     `json:"-"`              // 剩余的时间银行（入座期间有效）
+	ClientSeed string          `json:"-"`              // 参与洗牌的客户端种子
+	Left     bool              `json:"-"`              // 全押后离开房间，留在牌局中直到本局结算后移除
 	JoinTime time.Time         `json:"join_time"`
 }
 
@@ -58,6 +65,11 @@ type Room struct {
 	BigBlind        int                           `json:"big_blind"`
 	MaxPlayers      int                           `json:"max_players"`
 	IsPrivate       bool                          `json:"is_private"`
+	GameType        poker.GameType                `json:"game_type"` // 玩法（决定底牌张数、下注限制和牌力评估）
+	TrainingMode    bool                          `json:"training_mode"` // 训练模式（快照中向玩家显示自己的听牌和补牌）
+	AutoStart       bool                          `json:"auto_start"` // 有足够玩家时自动开始下一局（否则由玩家手动开始）
+	ActionTimeout   time.Duration                 `json:"-"` // 每次操作的时限
+	TimeBank        time.Duration                 `json:"-"` // 每位玩家入座时获得的时间银行（0表示不启用）
 	Status          RoomStatus                    `json:"status"`
 	Players         map[int64]*Player             `json:"players"`
 	CommunityCards  []poker.Card                  `json:"community_cards"`
@@ -66,10 +78,35 @@ type Room struct {
 	StateMachine    *statemachine.GameStateMachine `json:"-"`
 	BettingRound    *statemachine.BettingRound    `json:"-"`
 	Deck            *poker.Deck                   `json:"-"` // 牌堆
-	DealerPosition  int                           `json:"dealer_position"` // 庄家位置
+	DealerPosition  int                           `json:"dealer_position"` // 庄家座位位置（尚未开局时为-1）
+	ShowdownReached bool                          `json:"-"` // 本局是否进入摊牌（决定是否公开手牌）
+	AllInRunout     bool                          `json:"-"` // 本局是否已进入全押自动发牌
 	CreatedAt       time.Time                     `json:"created_at"`
 	UpdatedAt       time.Time                     `json:"updated_at"`
 	
+	// 事件推送
+	eventHandler  EventHandler `json:"-"`
+	pendingEvents []Event      `json:"-"`
+	
+	// 操作计时
+	turn turnTimer `json:"-"`
+	
+	// 自动开局时等待开始下一局的计时器
+	nextHand    *time.Timer `json:"-"`
+	nextHandSeq uint64      `json:"-"`
+	
+	// 洗牌随机数源（nil时使用可验证的公平洗牌）
+	rng poker.RNG `json:"-"`
+	
+	// 牌局重放时按已知的底牌和公共牌发牌（nil时正常洗牌）
+	replay *replayDeal `json:"-"`
+	
+	// 下一局的服务器种子（只公布其承诺）
+	nextServerSeed string `json:"-"`
+	
+	// 结算尚未持久化的玩家（玩家ID -> 尚未持久化的局数），由事件处理器持久化后调用SettleHand清除
+	unsettled map[int64]int `json:"-"`
+	
 	// 并发安全
 	mu sync.RWMutex `json:"-"`
 }
@@ -80,6 +117,12 @@ type GameSession struct {
 	StartTime   time.Time                  `json:"start_time"`
 	Participants []int64                    `json:"participants"` // 参与此局的玩家ID
 	GameLog     []string                   `json:"game_log"`     // 游戏日志
+	Contributions map[int64]int            `json:"contributions"` // 每位玩家本局的总投入（含已离开的玩家）
+	PotResults  []PotResult                `json:"pot_results,omitempty"` // 每个底池的结算结果
+	Seats       []HandPlayer               `json:"-"`                     // 发牌时参与本局的玩家（含底牌，仅用于牌局记录）
+	Actions     []HandAction               `json:"-"`                     // 本局按顺序发生的操作（含盲注）
+	DealOrder   []int64                    `json:"-"`                     // 发牌顺序（从庄家左手开始）
+	Fairness    *Fairness                  `json:"-"`                     // 公平洗牌数据（含未公开的服务器种子）
 	WinnerID    int64                      `json:"winner_id,omitempty"`
 	WinAmount   int                        `json:"win_amount,omitempty"`
 }
@@ -95,16 +138,22 @@ func NewRoom(id int64, name, chipLevel string, minChips, smallBlind, bigBlind, m
 		BigBlind:       bigBlind,
 		MaxPlayers:     maxPlayers,
 		IsPrivate:      isPrivate,
+		GameType:       poker.TexasHoldem,
+		ActionTimeout:  DefaultActionTimeout,
 		Status:         RoomWaiting,
 		Players:        make(map[int64]*Player),
+		unsettled:      make(map[int64]int),
 		CommunityCards: make([]poker.Card, 0, 5),
 		Pot:            0,
 		StateMachine:   statemachine.NewGameStateMachine(),
-		DealerPosition: 0,
+		DealerPosition: -1,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
 	
+	// 准备第一局的服务器种子
+	room.nextServerSeed = poker.GenerateServerSeed()
+	
 	// 设置状态机回调
 	room.setupStateMachineCallbacks()
 	
@@ -138,6 +187,14 @@ func (r *Room) setupStateMachineCallbacks() {
 	})
 }
 
+// SetRNG 设置洗牌使用的随机数源（测试和牌局重放时使用固定种子，nil表示使用crypto/rand）
+func (r *Room) SetRNG(rng poker.RNG) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	r.rng = rng
+}
+
 // AddPlayer 添加玩家到房间
 func (r *Room) AddPlayer(userID int64, username string, chips int) error {
 	r.mu.Lock()
@@ -149,7 +206,10 @@ func (r *Room) AddPlayer(userID int64, username string, chips int) error {
 	}
 	
 	// 检查玩家是否已在房间中
-	if _, exists := r.Players[userID]; exists {
+	if existing, exists := r.Players[userID]; exists {
+		if existing.Left {
+			return fmt.Errorf("您在本局中全押的筹码尚未结算，请等本局结束后再加入")
+		}
 		return fmt.Errorf("玩家已在房间中")
 	}
 	
@@ -172,6 +232,8 @@ func (r *Room) AddPlayer(userID int64, username string, chips int) error {
 		Position: position,
 		Status:   PlayerSitting,
 		Cards:    make([]poker.Card, 0, 2),
+		TimeBank: r.TimeBank,
+		ClientSeed: poker.GenerateClientSeed(),
 		JoinTime: time.Now(),
 	}
 	
@@ -188,6 +250,7 @@ func (r *Room) AddPlayer(userID int64, username string, chips int) error {
 
 // RemovePlayer 从房间移除玩家
 func (r *Room) RemovePlayer(userID int64) error {
+	defer r.flushEvents()
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
@@ -196,26 +259,37 @@ func (r *Room) RemovePlayer(userID int64) error {
 		return fmt.Errorf("玩家不在房间中")
 	}
 	
-	// 如果游戏正在进行，需要特殊处理
+	if player.Left {
+		return fmt.Errorf("玩家不在房间中")
+	}
+	
+	// 全押的玩家已没有需要做的决定，留在牌局中参与结算，本局结束后再移除
+	if r.Status == RoomPlaying && player.Status == PlayerAllIn {
+		player.Left = true
+		r.logGameAction(fmt.Sprintf("玩家 %s 离开房间，全押的筹码保留到本局结算", player.Username))
+		r.UpdatedAt = time.Now()
+		return nil
+	}
+	
+	// 如果游戏正在进行，还需要操作的玩家自动弃牌
 	if r.Status == RoomPlaying && player.Status == PlayerActive {
-		// 玩家自动弃牌
-		player.Status = PlayerFolded
 		r.logGameAction(fmt.Sprintf("玩家 %s 离开房间，自动弃牌", player.Username))
 		
-		// 检查是否需要触发游戏事件
-		if r.BettingRound != nil {
-			// 触发玩家离开事件
-			r.StateMachine.Transition(statemachine.PlayerLeft)
+		// 推进失败时玩家仍然离开，只记录错误
+		if err := r.forceFold(player, false); err != nil {
+			log.Printf("Failed to advance game in room %d after player %d left: %v", r.ID, userID, err)
 		}
 	}
 	
 	delete(r.Players, userID)
+	r.resetTurnTimer()
 	r.UpdatedAt = time.Now()
 	
-	// 如果房间空了，设置为等待状态
+	// 如果房间空了，设置为等待状态并取消自动开局
 	if len(r.Players) == 0 {
 		r.Status = RoomWaiting
 		r.StateMachine.Reset()
+		r.cancelNextHand()
 	}
 	
 	return nil
@@ -223,45 +297,112 @@ func (r *Room) RemovePlayer(userID int64) error {
 
 // StartGame 开始游戏
 func (r *Room) StartGame() error {
+	defer r.flushEvents()
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
 	// 检查是否可以开始游戏
-	if len(r.Players) < 2 {
-		return fmt.Errorf("至少需要2名玩家才能开始游戏")
+	if r.countPlayersWithChips() < 2 {
+		return fmt.Errorf("至少需要2名有筹码的玩家才能开始游戏")
 	}
 	
 	if r.Status != RoomWaiting {
 		return fmt.Errorf("房间状态不允许开始游戏")
 	}
 	
+	// 上一局结束后先回到等待状态
+	if r.StateMachine.GetCurrentState() == statemachine.GameEnd {
+		if err := r.StateMachine.Transition(statemachine.NextRound); err != nil {
+			return err
+		}
+	}
+	
+	// 修改房间之前确认状态机可以开局
+	if !r.StateMachine.CanTransition(statemachine.StartGame) {
+		return fmt.Errorf("当前牌局状态 %s 不允许开始游戏", r.StateMachine.GetCurrentState())
+	}
+	
+	// 手动开局时取消尚未触发的自动开局
+	r.cancelNextHand()
+	
+	// 开局失败时用于恢复的状态
+	stacks := make(map[int64]int, len(r.Players))
+	for playerID, player := range r.Players {
+		stacks[playerID] = player.Chips
+	}
+	dealerPosition := r.DealerPosition
+	pendingEvents := len(r.pendingEvents)
+	
+	// 重置房间状态
+	r.resetRoomState()
+	r.ShowdownReached = false
+	r.AllInRunout = false
+	
 	// 创建新的游戏会话
 	r.CurrentGame = &GameSession{
-		ID:           fmt.Sprintf("game_%d_%d", r.ID, time.Now().Unix()),
+		ID:           fmt.Sprintf("game_%d_%d", r.ID, time.Now().UnixNano()), // 持久化时按本局ID去重，同一秒内的两局也不能重复
 		StartTime:    time.Now(),
 		Participants: r.getActivePlayerIDs(),
 		GameLog:      make([]string, 0),
+		Contributions: make(map[int64]int),
 	}
 	
-	// 初始化牌堆
-	r.Deck = poker.NewDeck()
-	r.Deck.Shuffle()
-	
-	// 重置房间状态
-	r.resetRoomState()
-	
 	// 设置盲注
 	r.setupBlinds()
 	
+	// 按发牌顺序混合种子生成牌堆
+	r.prepareDeck()
+	
+	// 触发游戏开始事件（发底牌并下盲注）
+	if err := r.StateMachine.Transition(statemachine.StartGame); err != nil {
+		r.abortStart(stacks, dealerPosition, pendingEvents)
+		return err
+	}
+	
 	// 更新房间状态
 	r.Status = RoomPlaying
 	
-	// 触发游戏开始事件
-	return r.StateMachine.Transition(statemachine.StartGame)
+	started := map[string]interface{}{
+		"game_id":         r.CurrentGame.ID,
+		"participants":    r.CurrentGame.Participants,
+		"dealer_position": r.DealerPosition,
+		"small_blind":     r.SmallBlind,
+		"big_blind":       r.BigBlind,
+	}
+	// 客户端种子开局即公布；服务器种子要到本局结束才揭示，在此之前无法推导牌堆
+	if fairness := r.CurrentGame.Fairness; fairness != nil {
+		started["seed_hash"] = fairness.SeedHash
+		started["client_seeds"] = fairness.ClientSeeds
+	}
+	r.emit(EventHandStarted, started)
+	
+	// 盲注可能已让玩家全押，直接推进牌局
+	if err := r.advance(); err != nil {
+		r.abortStart(stacks, dealerPosition, pendingEvents)
+		return err
+	}
+	r.resetTurnTimer()
+	return nil
+}
+
+// abortStart 开局失败时撤销本局：退回盲注、恢复庄家位置、回到等待状态并丢弃本局产生的事件（调用方需持有写锁）
+func (r *Room) abortStart(stacks map[int64]int, dealerPosition, pendingEvents int) {
+	r.resetRoomState()
+	for playerID, player := range r.Players {
+		player.Chips = stacks[playerID]
+		player.Status = PlayerSitting
+	}
+	r.DealerPosition = dealerPosition
+	r.BettingRound = nil
+	r.CurrentGame = nil
+	r.Status = RoomWaiting
+	r.StateMachine.Reset()
+	r.pendingEvents = r.pendingEvents[:pendingEvents]
 }
 
 // ProcessPlayerAction 处理玩家操作
 func (r *Room) ProcessPlayerAction(userID int64, action statemachine.PlayerAction, amount int) (statemachine.ActionResult, error) {
+	defer r.flushEvents()
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
@@ -281,34 +422,130 @@ func (r *Room) ProcessPlayerAction(userID int64, action statemachine.PlayerActio
 		return statemachine.ActionResult{}, fmt.Errorf("当前没有下注轮")
 	}
 	
+	return r.applyAction(player, action, amount, false)
+}
+
+// applyAction 在下注轮中执行玩家操作并推进牌局（调用方需持有写锁）
+// timeout表示由操作计时器代替玩家执行的操作
+func (r *Room) applyAction(player *Player, action statemachine.PlayerAction, amount int, timeout bool) (statemachine.ActionResult, error) {
 	// 处理操作
-	result := r.BettingRound.ProcessAction(userID, action, amount)
+	result := r.BettingRound.ProcessAction(player.ID, action, amount)
 	
-	// 更新玩家状态
+	// 更新玩家状态（筹码扣除与下注轮记录在同一把锁内完成）
 	if result.Success {
-		player.LastAction = action
-		player.BetAmount = amount
+		player.LastAction = result.Action
+		player.HasActed = true
+		player.Chips -= result.Amount
+		player.BetAmount += result.Amount
 		
 		// 更新玩家状态
-		switch action {
-		case statemachine.Fold:
+		switch {
+		case result.Action == statemachine.Fold:
 			player.Status = PlayerFolded
-		case statemachine.AllIn:
+		case result.AllIn:
 			player.Status = PlayerAllIn
 		}
 		
 		// 记录游戏日志
-		r.logGameAction(fmt.Sprintf("玩家 %s %s", player.Username, action.String()))
+		if result.Amount > 0 {
+			r.logGameAction(fmt.Sprintf("玩家 %s %s %d", player.Username, result.Action.String(), result.Amount))
+		} else {
+			r.logGameAction(fmt.Sprintf("玩家 %s %s", player.Username, result.Action.String()))
+		}
+		
+		r.recordAction(player.ID, result.Action.Key(), result.Amount, result.AllIn, timeout)
+		data := actionEventData(player, result)
+		data["timeout"] = timeout
+		r.emit(EventPlayerAction, data)
 		
-		// 如果下注轮结束，触发相应事件
-		if result.NextEvent != 0 {
-			r.StateMachine.Transition(result.NextEvent)
+		// 下注轮结束后自动推进到下一街或结束本局
+		if err := r.advance(); err != nil {
+			return result, err
 		}
+		r.resetTurnTimer()
 	}
 	
 	return result, nil
 }
 
+// forceFold 不经过下注轮的校验直接让玩家弃牌并推进牌局（调用方需持有写锁）
+// 用于玩家离开和超时自动操作被拒绝的情况，已投入的筹码留在底池中；弃牌后可能只剩一名玩家或下注轮结束
+func (r *Room) forceFold(player *Player, timeout bool) error {
+	player.Status = PlayerFolded
+	
+	if r.BettingRound != nil {
+		r.BettingRound.ForceFold(player.ID)
+		r.recordAction(player.ID, statemachine.Fold.Key(), 0, false, timeout)
+		data := actionEventData(player, statemachine.ActionResult{Action: statemachine.Fold})
+		data["timeout"] = timeout
+		r.emit(EventPlayerAction, data)
+	}
+	
+	return r.advance()
+}
+
+// actionEventData 生成玩家操作事件的数据
+func actionEventData(player *Player, result statemachine.ActionResult) map[string]interface{} {
+	return map[string]interface{}{
+		"player_id":   player.ID,
+		"player_name": player.Username,
+		"action":      result.Action.Key(),
+		"amount":      result.Amount,
+		"all_in":      result.AllIn,
+		"chips":       player.Chips,
+	}
+}
+
+// advance 下注轮结束后自动推进牌局（调用方需持有写锁）
+// 只剩一名玩家时立即结束本局；能操作的玩家不足两人时不再下注，自动发完剩余公共牌
+func (r *Room) advance() error {
+	for r.Status == RoomPlaying && r.BettingRound != nil && r.BettingRound.IsCompleted() {
+		if r.countPlayersInHand() <= 1 {
+			return r.StateMachine.Transition(statemachine.AllOthersFolded)
+		}
+		
+		if !r.AllInRunout && len(r.getActivePlayerIDs()) <= 1 &&
+			r.StateMachine.GetCurrentState() != statemachine.River {
+			r.AllInRunout = true
+			r.logGameAction("所有玩家已全押，自动发完公共牌")
+			hands, request := r.allInHands()
+			r.emit(EventAllInRunout, map[string]interface{}{
+				"players": r.playersInHand(),
+				"hands":   hands,
+			})
+			// 胜率的计算量较大（奥马哈尤甚），推送事件前在房间锁之外计算
+			r.pendingEvents[len(r.pendingEvents)-1].equity = request
+		}
+		
+		if err := r.StateMachine.Transition(statemachine.BettingComplete); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allInHands 公开全押后仍在牌局中玩家的底牌，并准备计算当前公共牌下各自胜率的请求（限制了计算量，调用方需持有写锁）
+// 已弃牌玩家的底牌不公开，因此不作为死牌参与计算
+func (r *Room) allInHands() ([]map[string]interface{}, *equity.Request) {
+	playerIDs := r.playersInHand()
+	request := &equity.Request{
+		GameType:   r.GameType,
+		Board:      append([]poker.Card(nil), r.CommunityCards...),
+		ExactLimit: liveEquityExactLimit,
+		Iterations: liveEquityIterations,
+	}
+	hands := make([]map[string]interface{}, 0, len(playerIDs))
+	for _, playerID := range playerIDs {
+		cards := append([]poker.Card(nil), r.Players[playerID].Cards...)
+		request.Hands = append(request.Hands, cards)
+		hands = append(hands, map[string]interface{}{
+			"player_id": playerID,
+			"cards":     cards,
+		})
+	}
+	return hands, request
+}
+
 // 私有方法
 
 // findAvailablePosition 找到可用的座位位置
@@ -327,27 +564,121 @@ func (r *Room) findAvailablePosition() int {
 	return -1
 }
 
-// getActivePlayerIDs 获取活跃玩家ID列表
+// getActivePlayerIDs 获取活跃玩家ID列表（按座位顺序）
 func (r *Room) getActivePlayerIDs() []int64 {
 	var playerIDs []int64
-	for id, player := range r.Players {
-		if player.Status == PlayerSitting || player.Status == PlayerActive {
-			playerIDs = append(playerIDs, id)
+	for _, player := range r.playersBySeat() {
+		if player.Status == PlayerActive {
+			playerIDs = append(playerIDs, player.ID)
+		}
+	}
+	return playerIDs
+}
+
+// playersBySeat 获取按座位位置排序的玩家列表
+func (r *Room) playersBySeat() []*Player {
+	players := make([]*Player, 0, len(r.Players))
+	for _, player := range r.Players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Position < players[j].Position
+	})
+	return players
+}
+
+// playersInHand 获取仍在牌局中（未弃牌）的玩家ID列表（按座位顺序）
+func (r *Room) playersInHand() []int64 {
+	var playerIDs []int64
+	for _, player := range r.playersBySeat() {
+		if player.Status == PlayerActive || player.Status == PlayerAllIn {
+			playerIDs = append(playerIDs, player.ID)
 		}
 	}
 	return playerIDs
 }
 
+// countPlayersInHand 统计仍在牌局中（未弃牌）的玩家数量
+func (r *Room) countPlayersInHand() int {
+	return len(r.playersInHand())
+}
+
+// countPlayersWithChips 统计有筹码可以参与下一局的玩家数量
+func (r *Room) countPlayersWithChips() int {
+	count := 0
+	for _, player := range r.Players {
+		if player.Chips > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// nextActivePlayer 获取座位位置position之后（顺时针）第一个活跃玩家
+func (r *Room) nextActivePlayer(position int) *Player {
+	var first *Player
+	for _, player := range r.playersBySeat() {
+		if player.Status != PlayerActive {
+			continue
+		}
+		if player.Position > position {
+			return player
+		}
+		if first == nil {
+			first = player
+		}
+	}
+	return first
+}
+
+// actionOrder 获取从座位位置position左手开始的行动顺序（只包含还能操作的玩家）
+func (r *Room) actionOrder(position int) []int64 {
+	players := r.playersBySeat()
+	
+	order := make([]int64, 0, len(players))
+	for _, player := range players {
+		if player.Position > position && player.Status == PlayerActive {
+			order = append(order, player.ID)
+		}
+	}
+	for _, player := range players {
+		if player.Position <= position && player.Status == PlayerActive {
+			order = append(order, player.ID)
+		}
+	}
+	return order
+}
+
+// newBettingRound 按行动顺序创建下注轮，玩家当前筹码即为本轮的剩余筹码
+func (r *Room) newBettingRound(order []int64) *statemachine.BettingRound {
+	stacks := make(map[int64]int, len(order))
+	for _, playerID := range order {
+		stacks[playerID] = r.Players[playerID].Chips
+	}
+	
+	return statemachine.NewBettingRound(statemachine.BettingConfig{
+		Players:   order,
+		Stacks:    stacks,
+		BigBlind:  r.BigBlind,
+		PotLimit:  r.GameType.PotLimit(),
+		PotBefore: r.Pot,
+	})
+}
+
 // resetRoomState 重置房间状态
 func (r *Room) resetRoomState() {
 	r.CommunityCards = make([]poker.Card, 0, 5)
 	r.Pot = 0
 	
-	// 重置所有玩家状态
+	// 重置所有玩家状态（没有筹码的玩家本局只能旁观）
 	for _, player := range r.Players {
 		player.Status = PlayerActive
+		if player.Chips <= 0 {
+			player.Status = PlayerSitting
+		}
 		player.Cards = make([]poker.Card, 0, 2)
 		player.LastAction = 0
+		player.HasActed = false
 		player.BetAmount = 0
 		player.IsDealer = false
 		player.IsSmallBlind = false
@@ -355,60 +686,112 @@ func (r *Room) resetRoomState() {
 	}
 }
 
-// setupBlinds 设置盲注
+// setupBlinds 设置庄家和大小盲注位置（庄家按座位顺时针轮转）
+// 盲注在发牌后作为下注轮的第一笔下注登记
 func (r *Room) setupBlinds() {
 	playerIDs := r.getActivePlayerIDs()
 	if len(playerIDs) < 2 {
 		return
 	}
 	
-	// 设置庄家位置（轮转）
-	if r.DealerPosition >= len(playerIDs) {
-		r.DealerPosition = 0
-	}
-	
 	// 设置庄家、小盲注、大盲注
-	dealerID := playerIDs[r.DealerPosition]
-	smallBlindID := playerIDs[(r.DealerPosition+1)%len(playerIDs)]
-	bigBlindID := playerIDs[(r.DealerPosition+2)%len(playerIDs)]
+	dealer := r.nextActivePlayer(r.DealerPosition)
+	smallBlind := r.nextActivePlayer(dealer.Position)
 	
 	// 对于只有2个玩家的情况，庄家是小盲注
 	if len(playerIDs) == 2 {
-		smallBlindID = dealerID
-		bigBlindID = playerIDs[(r.DealerPosition+1)%len(playerIDs)]
+		smallBlind = dealer
 	}
+	bigBlind := r.nextActivePlayer(smallBlind.Position)
 	
-	r.Players[dealerID].IsDealer = true
-	r.Players[smallBlindID].IsSmallBlind = true
-	r.Players[smallBlindID].BetAmount = r.SmallBlind
-	r.Players[bigBlindID].IsBigBlind = true
-	r.Players[bigBlindID].BetAmount = r.BigBlind
+	r.DealerPosition = dealer.Position
+	dealer.IsDealer = true
+	smallBlind.IsSmallBlind = true
+	bigBlind.IsBigBlind = true
+}
+
+// postBlinds 在下注轮中登记大小盲注并扣除筹码
+func (r *Room) postBlinds() {
+	for _, player := range r.playersBySeat() {
+		blind := 0
+		switch {
+		case player.IsSmallBlind:
+			blind = r.SmallBlind
+		case player.IsBigBlind:
+			blind = r.BigBlind
+		default:
+			continue
+		}
+		
+		posted := r.BettingRound.PostBlind(player.ID, blind)
+		player.Chips -= posted
+		player.BetAmount = posted
+		if player.Chips == 0 {
+			player.Status = PlayerAllIn
+		}
+		r.logGameAction(fmt.Sprintf("玩家 %s 下盲注 %d", player.Username, posted))
+		if player.IsSmallBlind {
+			r.recordAction(player.ID, ActionPostSmallBlind, posted, player.Chips == 0, false)
+		} else {
+			r.recordAction(player.ID, ActionPostBigBlind, posted, player.Chips == 0, false)
+		}
+	}
+}
+
+// bigBlindPosition 获取大盲注玩家的座位位置
+func (r *Room) bigBlindPosition() int {
+	for _, player := range r.Players {
+		if player.IsBigBlind {
+			return player.Position
+		}
+	}
+	return r.DealerPosition
+}
+
+// commitRoundBets 将当前下注轮的下注计入玩家本局投入和底池
+func (r *Room) commitRoundBets() {
+	if r.BettingRound == nil || r.CurrentGame == nil {
+		return
+	}
 	
-	// 更新底池
-	r.Pot = r.SmallBlind + r.BigBlind
+	for playerID, bet := range r.BettingRound.GetPlayerBets() {
+		r.CurrentGame.Contributions[playerID] += bet
+		r.Pot += bet
+	}
+	for _, player := range r.Players {
+		player.BetAmount = 0
+	}
+	r.BettingRound = nil
 }
 
 // startPreFlop 开始发牌阶段
 func (r *Room) startPreFlop() error {
-	// 给每个活跃玩家发2张底牌
-	for _, player := range r.Players {
-		if player.Status == PlayerActive {
-			player.Cards = []poker.Card{
-				r.Deck.Deal(),
-				r.Deck.Deal(),
-			}
+	// 从庄家左手开始每人每轮发一张，直到每个活跃玩家拿到玩法规定的底牌张数
+	holeCards := r.GameType.HoleCards()
+	for _, playerID := range r.CurrentGame.DealOrder {
+		r.Players[playerID].Cards = make([]poker.Card, 0, holeCards)
+	}
+	for round := 0; round < holeCards; round++ {
+		for _, playerID := range r.CurrentGame.DealOrder {
+			player := r.Players[playerID]
+			player.Cards = append(player.Cards, r.Deck.Deal())
 		}
 	}
 	
-	// 创建下注轮
-	r.BettingRound = statemachine.NewBettingRound(r.getActivePlayerIDs())
+	r.logGameAction(fmt.Sprintf("开始发牌，每位玩家获得%d张底牌", holeCards))
+	r.recordSeats()
 	
-	r.logGameAction("开始发牌，每位玩家获得2张底牌")
+	// 创建下注轮：翻牌前从大盲注左手开始行动，盲注作为第一笔下注
+	r.BettingRound = r.newBettingRound(r.actionOrder(r.bigBlindPosition()))
+	r.postBlinds()
 	return nil
 }
 
 // dealFlop 发翻牌（3张公共牌）
 func (r *Room) dealFlop() error {
+	// 上一轮下注计入底池
+	r.commitRoundBets()
+	
 	// 烧一张牌，然后发3张公共牌
 	r.Deck.Deal() // 烧牌
 	
@@ -416,67 +799,94 @@ func (r *Room) dealFlop() error {
 		r.CommunityCards = append(r.CommunityCards, r.Deck.Deal())
 	}
 	
-	// 创建新的下注轮
-	r.BettingRound = statemachine.NewBettingRound(r.getActivePlayerIDs())
+	// 创建新的下注轮（翻牌后从庄家左手开始行动）
+	r.BettingRound = r.newBettingRound(r.actionOrder(r.DealerPosition))
 	
 	r.logGameAction("翻牌：发出3张公共牌")
+	r.emitStreetChanged()
 	return nil
 }
 
 // dealTurn 发转牌（第4张公共牌）
 func (r *Room) dealTurn() error {
+	// 上一轮下注计入底池
+	r.commitRoundBets()
+	
 	// 烧一张牌，然后发1张公共牌
 	r.Deck.Deal() // 烧牌
 	r.CommunityCards = append(r.CommunityCards, r.Deck.Deal())
 	
-	// 创建新的下注轮
-	r.BettingRound = statemachine.NewBettingRound(r.getActivePlayerIDs())
+	// 创建新的下注轮（翻牌后从庄家左手开始行动）
+	r.BettingRound = r.newBettingRound(r.actionOrder(r.DealerPosition))
 	
 	r.logGameAction("转牌：发出第4张公共牌")
+	r.emitStreetChanged()
 	return nil
 }
 
 // dealRiver 发河牌（第5张公共牌）
 func (r *Room) dealRiver() error {
+	// 上一轮下注计入底池
+	r.commitRoundBets()
+	
 	// 烧一张牌，然后发1张公共牌
 	r.Deck.Deal() // 烧牌
 	r.CommunityCards = append(r.CommunityCards, r.Deck.Deal())
 	
-	// 创建新的下注轮
-	r.BettingRound = statemachine.NewBettingRound(r.getActivePlayerIDs())
+	// 创建新的下注轮（翻牌后从庄家左手开始行动）
+	r.BettingRound = r.newBettingRound(r.actionOrder(r.DealerPosition))
 	
 	r.logGameAction("河牌：发出第5张公共牌")
+	r.emitStreetChanged()
 	return nil
 }
 
+// emitStreetChanged 记录进入新下注街的事件
+func (r *Room) emitStreetChanged() {
+	r.emit(EventStreetChanged, map[string]interface{}{
+		"community_cards": r.CommunityCards,
+		"pot":             r.Pot,
+		"runout":          r.AllInRunout,
+		"current_player":  r.BettingRound.GetCurrentPlayer(),
+	})
+}
+
 // showdown 摊牌阶段
 func (r *Room) showdown() error {
+	r.ShowdownReached = true
 	r.logGameAction("进入摊牌阶段")
 	
+	// 公开仍在牌局中的玩家底牌
+	hands := make([]map[string]interface{}, 0)
+	for _, playerID := range r.playersInHand() {
+		player := r.Players[playerID]
+		hand := map[string]interface{}{
+			"player_id": player.ID,
+			"cards":     player.Cards,
+		}
+		if madeHand, ok := r.evaluatePlayerHand(player.Cards); ok {
+			hand["hand_type"] = madeHand.Type.String()
+			hand["description"] = poker.DescribeHand(madeHand, player.Cards)
+		}
+		if low, ok := r.evaluatePlayerLow(player.Cards); ok {
+			hand["low_hand"] = low.String()
+		}
+		hands = append(hands, hand)
+	}
+	r.emit(EventShowdown, map[string]interface{}{
+		"hands":           hands,
+		"community_cards": r.CommunityCards,
+	})
+	
 	// 自动触发确定获胜者事件
 	return r.StateMachine.Transition(statemachine.DetermineWinner)
 }
 
 // endGame 结束游戏
 func (r *Room) endGame() error {
-	// 确定获胜者
-	winnerID := r.determineWinner()
-	
-	if winnerID != -1 {
-		winner := r.Players[winnerID]
-		winner.Chips += r.Pot
-		
-		// 更新游戏会话
-		if r.CurrentGame != nil {
-			r.CurrentGame.WinnerID = winnerID
-			r.CurrentGame.WinAmount = r.Pot
-		}
-		
-		r.logGameAction(fmt.Sprintf("玩家 %s 获胜，赢得 %d 筹码", winner.Username, r.Pot))
-	}
-	
-	// 移动庄家位置到下一个玩家
-	r.DealerPosition = (r.DealerPosition + 1) % len(r.getActivePlayerIDs())
+	// 最后一轮下注计入底池，然后按主池/边池结算
+	r.commitRoundBets()
+	r.settlePots()
 	
 	// 重置下注轮
 	r.BettingRound = nil
@@ -485,42 +895,183 @@ func (r *Room) endGame() error {
 	r.Status = RoomWaiting
 	
 	r.logGameAction("游戏结束")
+	if r.CurrentGame != nil {
+		ended := map[string]interface{}{
+			"game_id":         r.CurrentGame.ID,
+			"pot_results":     r.CurrentGame.PotResults,
+			"winner_id":       r.CurrentGame.WinnerID,
+			"win_amount":      r.CurrentGame.WinAmount,
+			"showdown":        r.ShowdownReached,
+			"community_cards": r.CommunityCards,
+			"next_seed_hash":  r.nextSeedHash(),
+		}
+		// 本局结束后公开服务器种子，供玩家验证洗牌
+		if fairness := r.CurrentGame.Fairness; fairness != nil {
+			ended["server_seed"] = fairness.ServerSeed
+			ended["seed_hash"] = fairness.SeedHash
+		}
+		record := r.buildHandRecord()
+		for _, player := range record.Players {
+			r.unsettled[player.ID]++
+		}
+		r.emitHandEnded(record, ended)
+	}
+	
+	// 全押后离开的玩家在结算后移除
+	for playerID, player := range r.Players {
+		if player.Left {
+			delete(r.Players, playerID)
+		}
+	}
+	if len(r.Players) == 0 {
+		r.StateMachine.Reset()
+		r.cancelNextHand()
+	}
 	return nil
 }
 
-// determineWinner 确定获胜者
-func (r *Room) determineWinner() int64 {
-	activePlayerIDs := r.getActivePlayerIDs()
-	if len(activePlayerIDs) == 0 {
-		return -1
+// evaluatePlayerHand 按房间玩法评估底牌与当前公共牌组成的最佳牌型（翻牌前或牌无效时返回false）
+func (r *Room) evaluatePlayerHand(holeCards []poker.Card) (poker.Hand, bool) {
+	hand, err := r.GameType.Evaluate(holeCards, r.CommunityCards)
+	if err != nil {
+		return poker.Hand{}, false
+	}
+	return hand, true
+}
+
+// evaluatePlayerLow 高低牌玩法中评估底牌与当前公共牌组成的合格低牌（没有合格低牌或不是高低牌玩法时返回false）
+func (r *Room) evaluatePlayerLow(holeCards []poker.Card) (poker.LowHand, bool) {
+	if !r.GameType.HiLo() {
+		return poker.LowHand{}, false
+	}
+	low, err := r.GameType.EvaluateLow(holeCards, r.CommunityCards)
+	if err != nil || !low.Qualified() {
+		return poker.LowHand{}, false
+	}
+	return low, true
+}
+
+// settlePots 构建主池和边池并分配给获胜者，结果记录在游戏会话中
+func (r *Room) settlePots() {
+	if r.CurrentGame == nil {
+		return
+	}
+	
+	// 已离开房间或已弃牌的玩家只贡献筹码，不参与分配
+	folded := make(map[int64]bool)
+	for playerID := range r.CurrentGame.Contributions {
+		if player, exists := r.Players[playerID]; !exists || player.Status == PlayerFolded {
+			folded[playerID] = true
+		}
 	}
 	
-	// 如果只有一个活跃玩家，直接获胜
-	if len(activePlayerIDs) == 1 {
-		return activePlayerIDs[0]
+	// 评估仍在牌局中的玩家手牌（高低牌玩法同时评估低牌）
+	hands := make(map[int64]poker.Hand)
+	lows := make(map[int64]poker.LowHand)
+	for playerID, player := range r.Players {
+		if player.Status == PlayerFolded || len(player.Cards) == 0 {
+			continue
+		}
+		if hand, ok := r.evaluatePlayerHand(player.Cards); ok {
+			hands[playerID] = hand
+		}
+		if low, ok := r.evaluatePlayerLow(player.Cards); ok {
+			lows[playerID] = low
+		}
 	}
 	
-	// 比较手牌强度
-	var bestHand poker.Hand
-	var winnerID int64 = -1
+	pots := BuildPots(r.CurrentGame.Contributions, folded)
+	seatOrder := r.seatOrderFromDealer()
+	var results []PotResult
+	if r.GameType.HiLo() {
+		results = AwardHiLoPots(pots, hands, lows, seatOrder)
+	} else {
+		results = AwardPots(pots, hands, seatOrder)
+	}
 	
-	for _, playerID := range activePlayerIDs {
-		player := r.Players[playerID]
-		if player.Status != PlayerFolded {
-			// 组合玩家手牌和公共牌
-			allCards := append(player.Cards, r.CommunityCards...)
-			if len(allCards) == 7 {
-				hand := poker.EvaluateHand(allCards)
-				
-				if winnerID == -1 || poker.CompareHands(hand, bestHand) > 0 {
-					bestHand = hand
-					winnerID = playerID
-				}
+	totals := make(map[int64]int)
+	for _, result := range results {
+		for playerID, share := range result.Shares {
+			r.Players[playerID].Chips += share
+			totals[playerID] += share
+		}
+		
+		potName := "主池"
+		if result.Index > 0 {
+			potName = fmt.Sprintf("边池%d", result.Index)
+		}
+		for _, playerID := range result.Winners {
+			message := fmt.Sprintf("玩家 %s 赢得%s %d 筹码", r.Players[playerID].Username, potName, result.Shares[playerID])
+			if len(result.LowWinners) > 0 {
+				message += fmt.Sprintf("（%s）", hiLoRole(result, playerID))
 			}
+			r.logGameAction(message)
+		}
+	}
+	
+	// 记录赢得最多筹码的玩家作为本局获胜者，赢得同样多时取从庄家左手开始最先的玩家
+	r.CurrentGame.PotResults = results
+	r.CurrentGame.WinAmount = 0
+	for _, playerID := range seatOrder {
+		total, won := totals[playerID]
+		if !won {
+			continue
+		}
+		r.CurrentGame.WinAmount += total
+		if r.CurrentGame.WinnerID == 0 || total > totals[r.CurrentGame.WinnerID] {
+			r.CurrentGame.WinnerID = playerID
+		}
+	}
+}
+
+// hiLoRole 玩家在高低牌拆分中赢得的部分
+func hiLoRole(result PotResult, playerID int64) string {
+	high := containsPlayer(result.HighWinners, playerID)
+	low := containsPlayer(result.LowWinners, playerID)
+	switch {
+	case high && low:
+		return "高牌和低牌"
+	case low:
+		return "低牌 " + result.LowHand
+	default:
+		return "高牌"
+	}
+}
+
+// containsPlayer 判断玩家是否在列表中
+func containsPlayer(playerIDs []int64, playerID int64) bool {
+	for _, id := range playerIDs {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// seatOrderFromDealer 获取从庄家左手开始的座位顺序（用于分配零头）
+func (r *Room) seatOrderFromDealer() []int64 {
+	players := make([]*Player, 0, len(r.Players))
+	dealerPosition := -1
+	for _, player := range r.Players {
+		players = append(players, player)
+		if player.IsDealer {
+			dealerPosition = player.Position
 		}
 	}
 	
-	return winnerID
+	// 按相对庄家的距离排序，庄家本人排在最后
+	distance := func(position int) int {
+		return (position - dealerPosition - 1 + r.MaxPlayers) % r.MaxPlayers
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return distance(players[i].Position) < distance(players[j].Position)
+	})
+	
+	order := make([]int64, len(players))
+	for i, player := range players {
+		order[i] = player.ID
+	}
+	return order
 }
 
 // logGameAction 记录游戏操作
@@ -532,6 +1083,81 @@ func (r *Room) logGameAction(action string) {
 	}
 }
 
+// HasPlayer 检查玩家是否在房间中（全押后离开、等待结算的玩家不算）
+func (r *Room) HasPlayer(userID int64) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	player, exists := r.Players[userID]
+	return exists && !player.Left
+}
+
+// HasUnsettledChips 检查玩家在本房间是否还有未结算的筹码：参与了正在进行的一局，或上一局的结算尚未持久化
+func (r *Room) HasUnsettledChips(userID int64) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	if r.unsettled[userID] > 0 {
+		return true
+	}
+	return r.Status == RoomPlaying && r.CurrentGame != nil && containsPlayer(r.CurrentGame.Participants, userID)
+}
+
+// SettleHand 一局的结算持久化后，清除这些玩家的未结算标记
+func (r *Room) SettleHand(playerIDs []int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	for _, playerID := range playerIDs {
+		if r.unsettled[playerID] <= 1 {
+			delete(r.unsettled, playerID)
+		} else {
+			r.unsettled[playerID]--
+		}
+	}
+}
+
+// PlayerIDs 获取房间内所有玩家ID（按座位顺序）
+func (r *Room) PlayerIDs() []int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	players := r.playersBySeat()
+	ids := make([]int64, len(players))
+	for i, player := range players {
+		ids[i] = player.ID
+	}
+	return ids
+}
+
+// GetPlayer 获取房间内玩家的副本
+func (r *Room) GetPlayer(userID int64) (Player, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	player, exists := r.Players[userID]
+	if !exists {
+		return Player{}, false
+	}
+	return *player, true
+}
+
+// CanStart 检查房间是否满足开始游戏的条件
+func (r *Room) CanStart() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	return r.Status == RoomWaiting && r.countPlayersWithChips() >= 2
+}
+
+// PlayerCount 获取房间内玩家数量
+func (r *Room) PlayerCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	return len(r.Players)
+}
+
 // GetRoomInfo 获取房间信息（用于广播）
 func (r *Room) GetRoomInfo() map[string]interface{} {
 	r.mu.RLock()
@@ -540,14 +1166,28 @@ func (r *Room) GetRoomInfo() map[string]interface{} {
 	return map[string]interface{}{
 		"id":              r.ID,
 		"name":            r.Name,
+		"chip_level":      r.ChipLevel,
+		"min_chips":       r.MinChips,
+		"max_players":     r.MaxPlayers,
+		"current_players": len(r.Players),
+		"is_private":      r.IsPrivate,
+		"game_type":       r.GameType,
+		"game_name":       r.GameType.String(),
+		"hand_rankings":   handRankings(r.GameType),
+		"training_mode":   r.TrainingMode,
+		"auto_start":      r.AutoStart,
+		"action_timeout":  int(r.ActionTimeout / time.Second),
+		"time_bank":       int(r.TimeBank / time.Second),
 		"status":          r.Status,
-		"players":         r.Players,
+		"players":         r.playerViews(0),
 		"community_cards": r.CommunityCards,
 		"pot":             r.Pot,
 		"current_state":   r.StateMachine.GetCurrentState().String(),
 		"dealer_position": r.DealerPosition,
 		"small_blind":     r.SmallBlind,
 		"big_blind":       r.BigBlind,
+		"next_seed_hash":  r.nextSeedHash(),
+		"created_at":      r.CreatedAt,
 		"updated_at":      r.UpdatedAt,
 	}
 } 
\ No newline at end of file
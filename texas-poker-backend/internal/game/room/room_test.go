@@ -0,0 +1,478 @@
+// 房间引擎测试
+// 作用：用固定的底牌和公共牌驱动房间引擎，校验玩家操作的处理、牌局中离开、摊牌结算、开局失败时的回滚、自动开局计时的取消和操作超时的自动操作
+
+package room
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/statemachine"
+)
+
+// testRoom 测试用的房间和按顺序收到的事件
+type testRoom struct {
+	*Room
+	eventsMu sync.Mutex
+	events   []Event
+}
+
+// newTestRoom 创建大小盲注为5/10的房间，玩家1、2、3……依次坐在座位0、1、2……，每人筹码为chips
+// holeCards按玩家顺序给出底牌，board为公共牌（不足的部分用其余的牌补齐）；第一局玩家1是庄家
+func newTestRoom(t *testing.T, chips int, holeCards []string, board string) *testRoom {
+	t.Helper()
+	tr := &testRoom{Room: NewRoom(1, "测试桌", "low", 0, 5, 10, 6, false)}
+	tr.ActionTimeout = time.Hour
+
+	deal := &replayDeal{holeCards: make(map[int64][]poker.Card), board: mustCards(t, board)}
+	for i, cards := range holeCards {
+		playerID := int64(i + 1)
+		if err := tr.AddPlayer(playerID, string(rune('a'+i)), chips); err != nil {
+			t.Fatalf("AddPlayer(%d): %v", playerID, err)
+		}
+		deal.holeCards[playerID] = mustCards(t, cards)
+	}
+	tr.replay = deal
+
+	tr.SetEventHandler(func(event Event) {
+		tr.eventsMu.Lock()
+		defer tr.eventsMu.Unlock()
+		tr.events = append(tr.events, event)
+	})
+	t.Cleanup(func() {
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		tr.stopTurnTimer()
+		tr.cancelNextHand()
+	})
+	return tr
+}
+
+// eventTypes 已收到的事件类型
+func (tr *testRoom) eventTypes() []string {
+	tr.eventsMu.Lock()
+	defer tr.eventsMu.Unlock()
+
+	types := make([]string, 0, len(tr.events))
+	for _, event := range tr.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+// chips 各玩家当前的筹码
+func (tr *testRoom) chips() map[int64]int {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	chips := make(map[int64]int, len(tr.Players))
+	for playerID, player := range tr.Players {
+		chips[playerID] = player.Chips
+	}
+	return chips
+}
+
+func TestStartGameRollback(t *testing.T) {
+	tr := newTestRoom(t, 1000, []string{"AsAd", "KsKd", "QsQd"}, "2c7h9d3s8h")
+
+	// 发完底牌、下完盲注后失败
+	tr.StateMachine.SetStateCallback(statemachine.PreFlop, func() error {
+		if err := tr.startPreFlop(); err != nil {
+			return err
+		}
+		return errors.New("发牌失败")
+	})
+
+	if err := tr.StartGame(); err == nil {
+		t.Fatalf("开局回调失败时期望返回错误")
+	}
+	if tr.Status != RoomWaiting || tr.StateMachine.GetCurrentState() != statemachine.WaitingForPlayers {
+		t.Errorf("开局失败后房间状态 = %s/%s，期望回到等待状态", tr.Status, tr.StateMachine.GetCurrentState())
+	}
+	if tr.CurrentGame != nil || tr.BettingRound != nil || tr.DealerPosition != -1 {
+		t.Errorf("开局失败后仍保留了本局的牌局、下注轮或庄家位置")
+	}
+	for playerID, chips := range tr.chips() {
+		if chips != 1000 {
+			t.Errorf("玩家%d的筹码 = %d，期望退回盲注后为1000", playerID, chips)
+		}
+	}
+	if types := tr.eventTypes(); len(types) != 0 {
+		t.Errorf("开局失败时推送了事件 %v", types)
+	}
+
+	// 恢复正常的回调后可以重新开局
+	tr.setupStateMachineCallbacks()
+	if err := tr.StartGame(); err != nil {
+		t.Fatalf("回滚后重新开局: %v", err)
+	}
+	if tr.Status != RoomPlaying || tr.DealerPosition != 0 {
+		t.Errorf("重新开局后房间状态 = %s，庄家位置 = %d", tr.Status, tr.DealerPosition)
+	}
+}
+
+func TestScheduleNextHand(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel func(t *testing.T, tr *testRoom)
+		want   bool
+	}{
+		{name: "到时开始下一局", cancel: func(*testing.T, *testRoom) {}, want: true},
+		{name: "房间没有玩家时取消", cancel: func(t *testing.T, tr *testRoom) {
+			for _, playerID := range tr.PlayerIDs() {
+				if err := tr.RemovePlayer(playerID); err != nil {
+					t.Fatalf("RemovePlayer(%d): %v", playerID, err)
+				}
+			}
+		}},
+		{name: "手动开局时取消", cancel: func(t *testing.T, tr *testRoom) {
+			if err := tr.StartGame(); err != nil {
+				t.Fatalf("StartGame: %v", err)
+			}
+		}},
+		{name: "显式取消", cancel: func(t *testing.T, tr *testRoom) { tr.CancelNextHand() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTestRoom(t, 1000, []string{"AsAd", "KsKd"}, "")
+
+			started := make(chan struct{}, 1)
+			tr.ScheduleNextHand(20*time.Millisecond, func() { started <- struct{}{} })
+			tt.cancel(t, tr)
+
+			select {
+			case <-started:
+				if !tt.want {
+					t.Errorf("取消后仍开始了下一局")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.want {
+					t.Errorf("到时没有开始下一局")
+				}
+			}
+		})
+	}
+}
+
+// waitForEvent 等待收到满足match的事件（计时器在后台触发）
+func (tr *testRoom) waitForEvent(t *testing.T, match func(Event) bool) Event {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		tr.eventsMu.Lock()
+		for _, event := range tr.events {
+			if match(event) {
+				tr.eventsMu.Unlock()
+				return event
+			}
+		}
+		tr.eventsMu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("没有收到期望的事件，已收到 %v", tr.eventTypes())
+	return Event{}
+}
+
+// mustAct 执行一个必须成功的玩家操作
+func (tr *testRoom) mustAct(t *testing.T, playerID int64, action statemachine.PlayerAction, amount int) {
+	t.Helper()
+	result, err := tr.ProcessPlayerAction(playerID, action, amount)
+	if err != nil || !result.Success {
+		t.Fatalf("玩家%d %s %d: err=%v, %s", playerID, action.Key(), amount, err, result.Message)
+	}
+}
+
+func TestTurnTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		before     func(t *testing.T, tr *testRoom) // 超时前的操作（玩家3是大盲注，玩家1最先行动）
+		timedOut   int64
+		wantAction string
+		wantStatus PlayerStatus
+	}{
+		{
+			name:       "需要跟注时超时自动弃牌",
+			timedOut:   1,
+			wantAction: statemachine.Fold.Key(),
+			wantStatus: PlayerFolded,
+		},
+		{
+			name: "可以过牌时超时自动过牌",
+			before: func(t *testing.T, tr *testRoom) {
+				tr.mustAct(t, 1, statemachine.Call, 0)
+				tr.mustAct(t, 2, statemachine.Call, 0)
+			},
+			timedOut:   3,
+			wantAction: statemachine.Check.Key(),
+			wantStatus: PlayerActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTestRoom(t, 1000, []string{"AsAd", "KsKd", "QsQd"}, "2c7h9d3s8h")
+			if err := tr.StartGame(); err != nil {
+				t.Fatalf("StartGame: %v", err)
+			}
+			if tt.before != nil {
+				tt.before(t, tr)
+			}
+
+			// 只缩短当前玩家的操作时限
+			tr.mu.Lock()
+			tr.ActionTimeout = 20 * time.Millisecond
+			tr.stopTurnTimer()
+			tr.resetTurnTimer()
+			tr.mu.Unlock()
+
+			action := tr.waitForEvent(t, func(event Event) bool {
+				return event.Type == EventPlayerAction && event.Data["timeout"] == true
+			})
+			if action.Data["player_id"] != tt.timedOut || action.Data["action"] != tt.wantAction {
+				t.Errorf("超时自动操作 = 玩家%v %v，期望玩家%d %s", action.Data["player_id"], action.Data["action"], tt.timedOut, tt.wantAction)
+			}
+			if player, _ := tr.GetPlayer(tt.timedOut); player.Status != tt.wantStatus {
+				t.Errorf("超时玩家状态 = %v，期望 %v", player.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// handRecord 已收到的一局结束事件中的牌局记录（没有结束时返回nil）
+func (tr *testRoom) handRecord() *HandRecord {
+	tr.eventsMu.Lock()
+	defer tr.eventsMu.Unlock()
+
+	for _, event := range tr.events {
+		if event.Type == EventHandEnded {
+			return event.Hand
+		}
+	}
+	return nil
+}
+
+func TestProcessPlayerAction(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     bool
+		playerID  int64
+		action    statemachine.PlayerAction
+		amount    int
+		wantErr   bool
+		wantOK    bool
+		wantChips int // 操作后该玩家的筹码
+		wantBet   int // 操作后该玩家本轮的下注
+	}{
+		{name: "不在房间中的玩家", start: true, playerID: 9, action: statemachine.Call, wantErr: true},
+		{name: "牌局尚未开始", playerID: 1, action: statemachine.Call, wantErr: true},
+		{name: "没有轮到的玩家", start: true, playerID: 2, action: statemachine.Call, wantChips: 995, wantBet: 5},
+		{name: "不需要跟注时不能过牌", start: true, playerID: 1, action: statemachine.Check, wantChips: 1000},
+		{name: "跟注大盲注", start: true, playerID: 1, action: statemachine.Call, wantOK: true, wantChips: 990, wantBet: 10},
+		{name: "加注到30", start: true, playerID: 1, action: statemachine.Raise, amount: 30, wantOK: true, wantChips: 970, wantBet: 30},
+		{name: "加注不足最小加注额", start: true, playerID: 1, action: statemachine.Raise, amount: 15, wantChips: 1000},
+		{name: "全押", start: true, playerID: 1, action: statemachine.AllIn, wantOK: true, wantChips: 0, wantBet: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTestRoom(t, 1000, []string{"AsAd", "KsKd", "QsQd"}, "2c7h9d3s8h")
+			if tt.start {
+				if err := tr.StartGame(); err != nil {
+					t.Fatalf("StartGame: %v", err)
+				}
+			}
+
+			result, err := tr.ProcessPlayerAction(tt.playerID, tt.action, tt.amount)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("期望返回错误")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessPlayerAction: %v", err)
+			}
+			if result.Success != tt.wantOK {
+				t.Errorf("Success = %v（%s），期望 %v", result.Success, result.Message, tt.wantOK)
+			}
+
+			player, _ := tr.GetPlayer(tt.playerID)
+			if player.Chips != tt.wantChips || player.BetAmount != tt.wantBet {
+				t.Errorf("筹码 = %d，本轮下注 = %d，期望 %d、%d", player.Chips, player.BetAmount, tt.wantChips, tt.wantBet)
+			}
+
+			var wantEvents []string
+			if tt.wantOK {
+				wantEvents = []string{EventPlayerAction}
+			}
+			var events []string
+			for _, eventType := range tr.eventTypes() {
+				if eventType == EventPlayerAction {
+					events = append(events, eventType)
+				}
+			}
+			if !reflect.DeepEqual(events, wantEvents) {
+				t.Errorf("操作事件 = %v，期望 %v", events, wantEvents)
+			}
+		})
+	}
+}
+
+func TestRemovePlayerDuringHand(t *testing.T) {
+	t.Run("轮到的玩家离开时自动弃牌，盲注留在底池", func(t *testing.T) {
+		tr := newTestRoom(t, 1000, []string{"AsAd", "KsKd", "QsQd"}, "2c7h9d3s8h")
+		if err := tr.StartGame(); err != nil {
+			t.Fatalf("StartGame: %v", err)
+		}
+		tr.mustAct(t, 1, statemachine.Call, 0)
+
+		// 小盲注离开，轮到大盲注
+		if err := tr.RemovePlayer(2); err != nil {
+			t.Fatalf("RemovePlayer: %v", err)
+		}
+		if tr.HasPlayer(2) || tr.Status != RoomPlaying {
+			t.Fatalf("离开后 HasPlayer = %v，房间状态 = %s", tr.HasPlayer(2), tr.Status)
+		}
+		if current := tr.BettingRound.GetCurrentPlayer(); current != 3 {
+			t.Errorf("离开后轮到玩家%d，期望大盲注玩家3", current)
+		}
+		if contributed := tr.BettingRound.GetPlayerBets()[2]; contributed != 5 {
+			t.Errorf("离开玩家的小盲注 = %d，期望留在底池中的5", contributed)
+		}
+
+		// 大盲注过牌后进入翻牌圈，剩下的两名玩家继续
+		tr.mustAct(t, 3, statemachine.Check, 0)
+		if state := tr.StateMachine.GetCurrentState(); state != statemachine.Flop || tr.Pot != 25 {
+			t.Errorf("状态 = %s，底池 = %d，期望翻牌圈、底池25", state, tr.Pot)
+		}
+	})
+
+	t.Run("只剩一名玩家时结束本局", func(t *testing.T) {
+		tr := newTestRoom(t, 1000, []string{"AsAd", "KsKd"}, "2c7h9d3s8h")
+		if err := tr.StartGame(); err != nil {
+			t.Fatalf("StartGame: %v", err)
+		}
+
+		// 单挑时庄家是小盲注并先行动
+		if err := tr.RemovePlayer(1); err != nil {
+			t.Fatalf("RemovePlayer: %v", err)
+		}
+		record := tr.handRecord()
+		if record == nil {
+			t.Fatalf("只剩一名玩家时没有结束本局，已收到 %v", tr.eventTypes())
+		}
+		if tr.Status != RoomWaiting || record.WinnerID != 2 {
+			t.Errorf("房间状态 = %s，获胜者 = %d，期望等待状态、玩家2获胜", tr.Status, record.WinnerID)
+		}
+		if chips := tr.chips(); chips[2] != 1005 {
+			t.Errorf("玩家2的筹码 = %d，期望赢得小盲注后为1005", chips[2])
+		}
+		if !tr.HasUnsettledChips(1) {
+			t.Errorf("离开的玩家在结算持久化前应仍有未结算的筹码")
+		}
+	})
+
+	t.Run("全押的玩家离开后留到结算", func(t *testing.T) {
+		tr := newTestRoom(t, 1000, []string{"AsAd", "KsKd", "QsQd"}, "2c7h9d3s8h")
+		if err := tr.StartGame(); err != nil {
+			t.Fatalf("StartGame: %v", err)
+		}
+		tr.mustAct(t, 1, statemachine.AllIn, 0)
+
+		if err := tr.RemovePlayer(1); err != nil {
+			t.Fatalf("RemovePlayer: %v", err)
+		}
+		if tr.HasPlayer(1) {
+			t.Errorf("全押后离开的玩家不应再算作在房间中")
+		}
+		if err := tr.AddPlayer(1, "a", 1000); err == nil {
+			t.Errorf("全押的筹码结算前不应能重新入座")
+		}
+
+		tr.mustAct(t, 2, statemachine.Fold, 0)
+		tr.mustAct(t, 3, statemachine.Fold, 0)
+
+		record := tr.handRecord()
+		if record == nil || record.WinnerID != 1 {
+			t.Fatalf("期望离开的全押玩家赢得本局，牌局记录 = %+v", record)
+		}
+		if _, exists := tr.Players[1]; exists {
+			t.Errorf("结算后应移除全押后离开的玩家")
+		}
+	})
+}
+
+func TestEndGameSettlement(t *testing.T) {
+	// 玩家1（庄家）100筹码全押，玩家2、3跟注后在翻牌圈各下注200形成边池，摊牌时AA赢主池、KK赢边池
+	tr := newTestRoom(t, 1000, []string{"AsAd", "KsKd", "QsQd"}, "2c7h9d3s8h")
+	tr.Players[1].Chips = 100
+	if err := tr.StartGame(); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+
+	tr.mustAct(t, 1, statemachine.AllIn, 0)
+	tr.mustAct(t, 2, statemachine.Call, 0)
+	tr.mustAct(t, 3, statemachine.Call, 0)
+	tr.mustAct(t, 2, statemachine.Bet, 200)
+	tr.mustAct(t, 3, statemachine.Call, 0)
+	for _, street := range []string{"转牌", "河牌"} {
+		for _, playerID := range []int64{2, 3} {
+			if result, err := tr.ProcessPlayerAction(playerID, statemachine.Check, 0); err != nil || !result.Success {
+				t.Fatalf("%s玩家%d过牌: err=%v, %s", street, playerID, err, result.Message)
+			}
+		}
+	}
+
+	record := tr.handRecord()
+	if record == nil {
+		t.Fatalf("摊牌后没有结束本局，已收到 %v", tr.eventTypes())
+	}
+	if tr.Status != RoomWaiting || !record.Showdown {
+		t.Errorf("房间状态 = %s，摊牌 = %v", tr.Status, record.Showdown)
+	}
+
+	wantPots := []struct {
+		amount  int
+		winners []int64
+	}{
+		{amount: 300, winners: []int64{1}},
+		{amount: 400, winners: []int64{2}},
+	}
+	if len(record.Pots) != len(wantPots) {
+		t.Fatalf("底池 = %+v，期望主池和一个边池", record.Pots)
+	}
+	for i, want := range wantPots {
+		pot := record.Pots[i]
+		if pot.Amount != want.amount || len(pot.Winners) != 1 || pot.Winners[0] != want.winners[0] {
+			t.Errorf("底池%d = %d 归 %v，期望 %d 归 %v", i, pot.Amount, pot.Winners, want.amount, want.winners)
+		}
+	}
+
+	wantChips := map[int64]int{1: 300, 2: 1100, 3: 700}
+	wantChanges := map[int64]int{1: 200, 2: 100, 3: -300}
+	chips := tr.chips()
+	for _, player := range record.Players {
+		if chips[player.ID] != wantChips[player.ID] || player.ChipsChange != wantChanges[player.ID] {
+			t.Errorf("玩家%d筹码 = %d，变化 = %d，期望 %d、%d",
+				player.ID, chips[player.ID], player.ChipsChange, wantChips[player.ID], wantChanges[player.ID])
+		}
+	}
+
+	// 结算持久化前玩家不能按数据库中的筹码重新入座
+	for playerID := int64(1); playerID <= 3; playerID++ {
+		if !tr.HasUnsettledChips(playerID) {
+			t.Errorf("玩家%d在结算持久化前应有未结算的筹码", playerID)
+		}
+	}
+	tr.SettleHand([]int64{1, 2, 3})
+	for playerID := int64(1); playerID <= 3; playerID++ {
+		if tr.HasUnsettledChips(playerID) {
+			t.Errorf("玩家%d结算持久化后仍有未结算的筹码", playerID)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// 牌桌快照
+// 作用：按观察者视角生成牌桌状态投影，隐藏对手和观战者不应看到的底牌
+
+package room
+
+import (
+	"sort"
+	"time"
+
+	"texas-poker-backend/internal/game/poker"
+)
+
+// PlayerView 观察者看到的玩家信息
+type PlayerView struct {
+	ID            int64                  `json:"id"`
+	Username      string                 `json:"username"`
+	Chips         int                    `json:"chips"`
+	Position      int                    `json:"position"`
+	SeatIndex     int                    `json:"seat_index"` // 与position相同，前端按座位渲染
+	Status        PlayerStatus           `json:"status"`
+	Cards         []poker.Card           `json:"cards,omitempty"` // 不可见时为空
+	CardCount     int                    `json:"card_count"`      // 手牌张数（隐藏时用于渲染牌背）
+	LastAction    string                 `json:"last_action,omitempty"`
+	BetAmount     int                    `json:"bet_amount"`
+	IsDealer      bool                   `json:"is_dealer"`
+	IsSmallBlind  bool                   `json:"is_small_blind"`
+	IsBigBlind    bool                   `json:"is_big_blind"`
+	IsCurrentUser bool                   `json:"is_current_user"`
+	IsCurrentTurn bool                   `json:"is_current_turn"`
+	TimeBank      int                    `json:"time_bank"`             // 剩余的时间银行（秒）
+	MadeHand      string                 `json:"made_hand,omitempty"`   // 底牌可见时与当前公共牌组成的牌型（翻牌后）
+	HandDetail    *poker.HandDescription `json:"hand_detail,omitempty"` // 牌型的中英文描述、五张牌和用到的底牌
+	LowHand       string                 `json:"low_hand,omitempty"`    // 高低牌玩法中底牌可见时的合格低牌（翻牌后）
+	Draws         *poker.DrawAnalysis    `json:"draws,omitempty"`       // 训练模式中观察者自己在翻牌和转牌圈的听牌分析
+}
+
+// TableSnapshot 按观察者视角生成的牌桌快照
+type TableSnapshot struct {
+	ID             int64        `json:"id"`
+	Name           string       `json:"name"`
+	Status         RoomStatus   `json:"status"`
+	State          string       `json:"state"`         // 游戏阶段英文标识（waiting/preflop/flop/...）
+	StateName      string       `json:"state_name"`    // 游戏阶段中文名称
+	GameType       string       `json:"game_type"`     // 玩法标识（holdem/short/plo/plo8）
+	GameName       string       `json:"game_name"`     // 玩法中文名称
+	PotLimit       bool         `json:"pot_limit"`     // 是否为底池限注
+	HoleCards      int          `json:"hole_cards"`    // 每位玩家的底牌张数
+	HandRankings   []string     `json:"hand_rankings"` // 该玩法的牌型名称，从大到小
+	TrainingMode   bool         `json:"training_mode"` // 训练模式（显示自己的听牌和补牌）
+	Players        []PlayerView `json:"players"`
+	CommunityCards []poker.Card `json:"community_cards"`
+	Pot            int          `json:"pot"`
+	DealerPosition int          `json:"dealer_position"`
+	SmallBlind     int          `json:"small_blind"`
+	BigBlind       int          `json:"big_blind"`
+	CurrentPlayer  int64        `json:"current_player"`            // 当前轮到操作的玩家，没有时为0
+	CurrentBet     int          `json:"current_bet"`               // 本轮最高下注
+	CallAmount     int          `json:"call_amount"`               // 观察者跟注需要补齐的金额
+	MinRaise       int          `json:"min_raise"`                 // 最小加注后的总下注
+	MaxRaise       int          `json:"max_raise"`                 // 观察者最多可以加注到的总下注
+	CanRaise       bool         `json:"can_raise"`                 // 观察者当前是否可以加注（不足额全押后可能只能跟注）
+	ActionTimeout  int          `json:"action_timeout"`            // 每次操作的时限（秒）
+	ActionDeadline *time.Time   `json:"action_deadline,omitempty"` // 当前行动玩家的操作截止时间
+	ViewerID       int64        `json:"viewer_id"`
+	IsSpectator    bool         `json:"is_spectator"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// SnapshotFor 生成指定观察者视角的牌桌快照
+// 自己的底牌可见；对手的底牌在摊牌前隐藏；观战者始终看不到底牌
+func (r *Room) SnapshotFor(viewerID int64) *TableSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, seated := r.Players[viewerID]
+
+	snapshot := &TableSnapshot{
+		ID:             r.ID,
+		Name:           r.Name,
+		Status:         r.Status,
+		State:          r.StateMachine.GetCurrentState().Key(),
+		StateName:      r.StateMachine.GetCurrentState().String(),
+		GameType:       string(r.GameType),
+		GameName:       r.GameType.String(),
+		PotLimit:       r.GameType.PotLimit(),
+		HoleCards:      r.GameType.HoleCards(),
+		HandRankings:   handRankings(r.GameType),
+		TrainingMode:   r.TrainingMode,
+		Players:        r.playerViews(viewerID),
+		CommunityCards: r.CommunityCards,
+		Pot:            r.Pot,
+		DealerPosition: r.DealerPosition,
+		SmallBlind:     r.SmallBlind,
+		BigBlind:       r.BigBlind,
+		ActionTimeout:  int(r.ActionTimeout / time.Second),
+		ViewerID:       viewerID,
+		IsSpectator:    !seated,
+		UpdatedAt:      r.UpdatedAt,
+	}
+
+	if r.Status == RoomPlaying && r.BettingRound != nil {
+		if current := r.BettingRound.GetCurrentPlayer(); current > 0 {
+			snapshot.CurrentPlayer = current
+			if !r.turn.deadline.IsZero() {
+				deadline := r.turn.deadline
+				snapshot.ActionDeadline = &deadline
+			}
+		}
+		snapshot.CurrentBet = r.BettingRound.GetCurrentBet()
+		snapshot.MinRaise = r.BettingRound.GetMinRaise()
+		if minRaise := snapshot.CurrentBet + r.BigBlind; snapshot.MinRaise < minRaise {
+			snapshot.MinRaise = minRaise
+		}
+		if seated {
+			snapshot.CallAmount = r.BettingRound.GetCallAmount(viewerID)
+			if chips := r.Players[viewerID].Chips; snapshot.CallAmount > chips {
+				snapshot.CallAmount = chips
+			}
+			snapshot.MaxRaise = r.BettingRound.GetMaxRaise(viewerID)
+			snapshot.CanRaise = r.BettingRound.CanRaise(viewerID)
+		}
+	}
+
+	return snapshot
+}
+
+// playerViews 生成按座位排序的玩家视图（调用方需持有读锁）
+// viewerID为0或不在座时按观战者处理
+func (r *Room) playerViews(viewerID int64) []PlayerView {
+	_, seated := r.Players[viewerID]
+
+	var currentPlayer int64
+	if r.Status == RoomPlaying && r.BettingRound != nil {
+		currentPlayer = r.BettingRound.GetCurrentPlayer()
+	}
+
+	views := make([]PlayerView, 0, len(r.Players))
+	for _, player := range r.Players {
+		view := PlayerView{
+			ID:            player.ID,
+			Username:      player.Username,
+			Chips:         player.Chips,
+			Position:      player.Position,
+			SeatIndex:     player.Position,
+			Status:        player.Status,
+			CardCount:     len(player.Cards),
+			BetAmount:     player.BetAmount,
+			IsDealer:      player.IsDealer,
+			IsSmallBlind:  player.IsSmallBlind,
+			IsBigBlind:    player.IsBigBlind,
+			IsCurrentUser: player.ID == viewerID,
+			IsCurrentTurn: currentPlayer > 0 && player.ID == currentPlayer,
+			TimeBank:      int(player.TimeBank / time.Second),
+		}
+		if player.HasActed {
+			view.LastAction = player.LastAction.Key()
+		}
+		if seated && r.cardsVisible(player, viewerID) {
+			view.Cards = player.Cards
+			if player.Status != PlayerFolded {
+				if hand, ok := r.evaluatePlayerHand(player.Cards); ok {
+					view.MadeHand = hand.Type.String()
+					description := poker.DescribeHand(hand, player.Cards)
+					view.HandDetail = &description
+				}
+				if low, ok := r.evaluatePlayerLow(player.Cards); ok {
+					view.LowHand = low.String()
+				}
+				if player.ID == viewerID {
+					view.Draws = r.drawAnalysis(player)
+				}
+			}
+		}
+		views = append(views, view)
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Position < views[j].Position
+	})
+	return views
+}
+
+// cardsVisible 判断入座的观察者能否看到玩家的底牌
+func (r *Room) cardsVisible(player *Player, viewerID int64) bool {
+	if player.ID == viewerID {
+		return true
+	}
+	// 摊牌后公开仍在牌局中的玩家底牌
+	return r.ShowdownReached && player.Status != PlayerFolded
+}
+
+// handRankings 玩法中从大到小的牌型名称
+func handRankings(gameType poker.GameType) []string {
+	rankings := gameType.HandRankings()
+	names := make([]string, len(rankings))
+	for i, handType := range rankings {
+		names[i] = handType.String()
+	}
+	return names
+}
+
+// drawAnalysis 训练模式中玩家在翻牌和转牌圈的听牌分析（不是训练模式或玩法不支持时为nil）
+func (r *Room) drawAnalysis(player *Player) *poker.DrawAnalysis {
+	if !r.TrainingMode || len(r.CommunityCards) < 3 || len(r.CommunityCards) > 4 {
+		return nil
+	}
+	analysis, err := r.GameType.AnalyzeDraws(player.Cards, r.CommunityCards)
+	if err != nil {
+		return nil
+	}
+	return analysis
+}
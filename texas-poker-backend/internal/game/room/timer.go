@@ -0,0 +1,172 @@
+// 操作计时器
+// 作用：为当前行动玩家设置操作时限，超时后先使用时间银行，用完后自动过牌或弃牌；以及自动开局时开始下一局的计时
+
+package room
+
+import (
+	"log"
+	"time"
+
+	"texas-poker-backend/internal/game/statemachine"
+)
+
+// DefaultActionTimeout 默认的每次操作时限
+const DefaultActionTimeout = 30 * time.Second
+
+// EventTurnStarted 轮到玩家操作（包含操作截止时间，进入时间银行时会再次发送）
+const EventTurnStarted = "turn_started"
+
+// turnTimer 当前行动玩家的计时状态
+type turnTimer struct {
+	timer     *time.Timer
+	round     *statemachine.BettingRound // 计时所属的下注轮
+	playerID  int64                      // 计时的玩家
+	deadline  time.Time                  // 操作截止时间
+	usingBank bool                       // 是否已进入时间银行
+	seq       uint64                     // 计时序号，用于忽略已过期的计时回调
+}
+
+// resetTurnTimer 轮到新的玩家操作时重新开始计时（调用方需持有写锁）
+// 行动玩家和下注轮都没有变化时保留原来的计时
+func (r *Room) resetTurnTimer() {
+	var current int64 = -1
+	if r.Status == RoomPlaying && r.BettingRound != nil {
+		current = r.BettingRound.GetCurrentPlayer()
+	}
+	if current == r.turn.playerID && r.BettingRound == r.turn.round && r.turn.timer != nil {
+		return
+	}
+
+	r.stopTurnTimer()
+	if current <= 0 {
+		return
+	}
+
+	r.turn.round = r.BettingRound
+	r.turn.playerID = current
+	r.startTurnTimer(r.ActionTimeout)
+}
+
+// startTurnTimer 为当前计时玩家启动一个时长为timeout的计时器并发出事件
+func (r *Room) startTurnTimer(timeout time.Duration) {
+	r.turn.seq++
+	seq := r.turn.seq
+	r.turn.deadline = time.Now().Add(timeout)
+	r.turn.timer = time.AfterFunc(timeout, func() {
+		r.onTurnTimeout(seq)
+	})
+
+	data := map[string]interface{}{
+		"player_id":       r.turn.playerID,
+		"deadline":        r.turn.deadline,
+		"timeout_seconds": int(timeout / time.Second),
+		"using_time_bank": r.turn.usingBank,
+	}
+	if player, exists := r.Players[r.turn.playerID]; exists {
+		data["time_bank"] = int(player.TimeBank / time.Second)
+	}
+	r.emit(EventTurnStarted, data)
+}
+
+// stopTurnTimer 停止计时，正在使用时间银行的玩家保留剩余的时间（调用方需持有写锁）
+func (r *Room) stopTurnTimer() {
+	if r.turn.timer != nil {
+		r.turn.timer.Stop()
+	}
+	if r.turn.usingBank {
+		if player, exists := r.Players[r.turn.playerID]; exists {
+			player.TimeBank = max(time.Until(r.turn.deadline), 0)
+		}
+	}
+	r.turn = turnTimer{seq: r.turn.seq}
+}
+
+// onTurnTimeout 操作超时：有时间银行时先使用时间银行，否则能过牌则过牌，不能则弃牌
+func (r *Room) onTurnTimeout(seq uint64) {
+	defer r.flushEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 玩家已经操作或计时已被重置
+	if seq != r.turn.seq || r.BettingRound == nil || r.BettingRound != r.turn.round {
+		return
+	}
+
+	player, exists := r.Players[r.turn.playerID]
+	if !exists || r.BettingRound.GetCurrentPlayer() != player.ID {
+		return
+	}
+
+	if !r.turn.usingBank && player.TimeBank > 0 {
+		r.turn.usingBank = true
+		bank := player.TimeBank
+		player.TimeBank = 0
+		r.logGameAction(player.Username + " 开始使用时间银行")
+		r.startTurnTimer(bank)
+		return
+	}
+
+	r.turn.usingBank = false
+	action := statemachine.Fold
+	if r.BettingRound.GetCallAmount(player.ID) == 0 {
+		action = statemachine.Check
+	}
+	r.logGameAction("玩家 " + player.Username + " 操作超时")
+	result, err := r.applyAction(player, action, 0, true)
+	if err != nil {
+		log.Printf("Failed to advance game in room %d after player %d timed out: %v", r.ID, player.ID, err)
+	}
+	if !result.Success {
+		// 自动操作被拒绝时直接弃牌，否则牌局会一直停在该玩家
+		log.Printf("Timeout %s of player %d in room %d was rejected (%s), folding instead", action.Key(), player.ID, r.ID, result.Message)
+		if err := r.forceFold(player, true); err != nil {
+			log.Printf("Failed to advance game in room %d after player %d timed out: %v", r.ID, player.ID, err)
+		}
+	}
+	// 推进失败时applyAction没有重新计时，这里确保轮到的玩家有新的计时
+	r.resetTurnTimer()
+}
+
+// ScheduleNextHand 在delay之后调用start开始下一局（用于自动开局），取代尚未触发的安排
+// 房间没有玩家、手动开局或房间被移除时取消；start在房间锁之外调用
+func (r *Room) ScheduleNextHand(delay time.Duration, start func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cancelNextHand()
+	if len(r.Players) == 0 {
+		return
+	}
+
+	seq := r.nextHandSeq
+	r.nextHand = time.AfterFunc(delay, func() {
+		r.mu.Lock()
+		current := seq == r.nextHandSeq && r.nextHand != nil
+		if current {
+			r.nextHand = nil
+		}
+		r.mu.Unlock()
+
+		if current {
+			start()
+		}
+	})
+}
+
+// CancelNextHand 取消尚未触发的自动开局
+func (r *Room) CancelNextHand() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cancelNextHand()
+}
+
+// cancelNextHand 取消尚未触发的自动开局（调用方需持有写锁）
+// 计时器可能已经触发并在等待房间锁，序号变化后回调不再开局
+func (r *Room) cancelNextHand() {
+	if r.nextHand != nil {
+		r.nextHand.Stop()
+		r.nextHand = nil
+	}
+	r.nextHandSeq++
+}
@@ -0,0 +1,388 @@
+// 下注轮引擎
+// 作用：按无限注或底池限注规则管理一轮下注，包括盲注、最小加注、底池限注的最大加注、不足额全押和大盲注选择权
+
+package statemachine
+
+import (
+	"fmt"
+)
+
+// BettingConfig 下注轮配置
+type BettingConfig struct {
+	Players  []int64       // 参与本轮下注的玩家，按行动顺序排列（第一个为首个行动者）
+	Stacks   map[int64]int // 每位玩家本轮开始时的剩余筹码
+	BigBlind int           // 大盲注，同时是最小下注额和最小加注增量
+
+	PotLimit  bool // 底池限注：加注后的总下注不能超过当前下注加上跟注后的底池
+	PotBefore int  // 本轮开始前底池中已有的筹码（底池限注计算最大加注用）
+}
+
+// BettingRound 下注轮管理
+type BettingRound struct {
+	players       []int64                // 参与下注的玩家ID列表（行动顺序）
+	currentPlayer int                    // 当前轮到的玩家索引，-1表示没有玩家需要操作
+	stacks        map[int64]int          // 每个玩家的剩余筹码
+	playerBets    map[int64]int          // 每个玩家本轮的下注金额
+	playerActions map[int64]PlayerAction // 每个玩家的最后操作
+	folded        map[int64]bool         // 已弃牌的玩家
+	allIn         map[int64]bool         // 已全押的玩家
+	acted         map[int64]bool         // 本轮已主动操作过的玩家（盲注不算操作）
+	raisesSeen    map[int64]int          // 玩家最后一次操作时已发生的完整加注次数
+	bigBlind      int                    // 大盲注
+	potLimit      bool                   // 是否为底池限注
+	potBefore     int                    // 本轮开始前底池中已有的筹码
+	currentBet    int                    // 当前最高下注
+	lastRaise     int                    // 最近一次完整加注的增量（最小加注增量）
+	lastFullBet   int                    // 最近一次完整下注/加注后的下注额
+	fullRaises    int                    // 本轮完整下注/加注的次数
+	completed     bool                   // 下注轮是否完成
+}
+
+// NewBettingRound 创建新的下注轮
+func NewBettingRound(config BettingConfig) *BettingRound {
+	br := &BettingRound{
+		players:       config.Players,
+		currentPlayer: 0,
+		stacks:        make(map[int64]int, len(config.Players)),
+		playerBets:    make(map[int64]int, len(config.Players)),
+		playerActions: make(map[int64]PlayerAction),
+		folded:        make(map[int64]bool),
+		allIn:         make(map[int64]bool),
+		acted:         make(map[int64]bool),
+		raisesSeen:    make(map[int64]int),
+		bigBlind:      config.BigBlind,
+		potLimit:      config.PotLimit,
+		potBefore:     config.PotBefore,
+		lastRaise:     config.BigBlind,
+	}
+
+	for _, playerID := range config.Players {
+		br.stacks[playerID] = config.Stacks[playerID]
+		if br.stacks[playerID] <= 0 {
+			br.allIn[playerID] = true
+		}
+	}
+
+	br.refresh(0)
+	return br
+}
+
+// PostBlind 登记盲注，返回实际投入的筹码（筹码不足时全押）
+// 其他玩家需要跟注到完整的盲注金额；盲注不算主动操作，因此大盲注保留选择权
+func (br *BettingRound) PostBlind(playerID int64, amount int) int {
+	if _, exists := br.stacks[playerID]; !exists || br.folded[playerID] {
+		return 0
+	}
+
+	posted := min(amount, br.stacks[playerID])
+	br.putChips(playerID, posted)
+
+	if amount > br.currentBet {
+		br.currentBet = amount
+		br.lastFullBet = amount
+	}
+
+	br.refresh(br.currentPlayer)
+	return posted
+}
+
+// GetCurrentPlayer 获取当前应该操作的玩家
+func (br *BettingRound) GetCurrentPlayer() int64 {
+	if br.completed || br.currentPlayer < 0 || br.currentPlayer >= len(br.players) {
+		return -1 // 无效玩家
+	}
+	return br.players[br.currentPlayer]
+}
+
+// ProcessAction 处理玩家操作
+// Bet和Raise的amount为操作后本轮的总下注额，其他操作忽略amount
+func (br *BettingRound) ProcessAction(playerID int64, action PlayerAction, amount int) ActionResult {
+	if br.completed {
+		return ActionResult{
+			Success: false,
+			Message: "本轮下注已结束",
+		}
+	}
+
+	// 验证是否为当前玩家
+	if playerID != br.GetCurrentPlayer() {
+		return ActionResult{
+			Success: false,
+			Message: "不是您的操作轮次",
+		}
+	}
+
+	// 没有人下注时加注视为下注，已有下注时下注视为加注
+	if action == Raise && br.currentBet == 0 {
+		action = Bet
+	} else if action == Bet && br.currentBet > 0 {
+		action = Raise
+	}
+
+	stack := br.stacks[playerID]
+	toCall := br.currentBet - br.playerBets[playerID]
+	var chips int
+
+	switch action {
+	case Fold:
+		br.folded[playerID] = true
+
+	case Check:
+		// 过牌（只有不需要补齐下注时才能过牌）
+		if toCall > 0 {
+			return br.reject("已有人下注，无法过牌")
+		}
+
+	case Call:
+		// 跟注到当前最高金额，筹码不足时全押跟注
+		if toCall == 0 {
+			return br.reject("当前无需跟注，请选择过牌")
+		}
+		chips = min(toCall, stack)
+
+	case Bet:
+		if amount <= 0 {
+			return br.reject("下注金额必须大于0")
+		}
+		if amount > stack {
+			return br.reject("下注金额超过剩余筹码")
+		}
+		if amount < br.bigBlind && amount < stack {
+			return br.reject(fmt.Sprintf("下注金额不能小于大盲注 %d", br.bigBlind))
+		}
+		if maxBet := br.GetMaxRaise(playerID); amount > maxBet {
+			return br.reject(fmt.Sprintf("底池限注下最多下注到 %d", maxBet))
+		}
+		chips = amount
+
+	case Raise:
+		if !br.CanRaise(playerID) {
+			return br.reject("当前不能加注，只能跟注或弃牌")
+		}
+		chips = amount - br.playerBets[playerID]
+		if chips > stack {
+			return br.reject("加注金额超过剩余筹码")
+		}
+		if amount < br.GetMinRaise() && chips < stack {
+			return br.reject(fmt.Sprintf("加注后的总下注至少为 %d", br.GetMinRaise()))
+		}
+		if maxRaise := br.GetMaxRaise(playerID); amount > maxRaise {
+			return br.reject(fmt.Sprintf("底池限注下最多加注到 %d", maxRaise))
+		}
+
+	case AllIn:
+		if stack == 0 {
+			return br.reject("没有可以全押的筹码")
+		}
+		// 全押超过跟注额即为加注，需要有加注的权利
+		if stack > toCall && !br.CanRaise(playerID) {
+			return br.reject("当前不能加注，只能跟注或弃牌")
+		}
+		if maxRaise := br.GetMaxRaise(playerID); br.playerBets[playerID]+stack > maxRaise {
+			return br.reject(fmt.Sprintf("底池限注下不能全押，最多加注到 %d", maxRaise))
+		}
+		chips = stack
+
+	default:
+		return br.reject("无效的操作类型")
+	}
+
+	br.putChips(playerID, chips)
+	if total := br.playerBets[playerID]; total > br.currentBet {
+		br.raiseTo(total)
+	}
+
+	br.acted[playerID] = true
+	br.raisesSeen[playerID] = br.fullRaises
+	br.playerActions[playerID] = action
+	br.refresh(br.currentPlayer + 1)
+
+	result := ActionResult{
+		Success: true,
+		Action:  action,
+		Amount:  chips,
+		AllIn:   br.allIn[playerID],
+	}
+
+	// 检查下注轮是否完成
+	if br.completed {
+		result.Message = fmt.Sprintf("玩家 %d %s 成功，下注轮结束", playerID, action.String())
+		result.NextEvent = BettingComplete
+		return result
+	}
+
+	result.Message = fmt.Sprintf("玩家 %d %s 成功", playerID, action.String())
+	return result
+}
+
+// ForceFold 让玩家在非自己回合时弃牌（例如离开房间），轮到该玩家时顺延到下一位
+func (br *BettingRound) ForceFold(playerID int64) {
+	if _, exists := br.stacks[playerID]; !exists || br.folded[playerID] || br.completed {
+		return
+	}
+
+	wasCurrent := br.GetCurrentPlayer() == playerID
+	br.folded[playerID] = true
+	br.playerActions[playerID] = Fold
+
+	if wasCurrent {
+		br.refresh(br.currentPlayer + 1)
+	} else {
+		br.refresh(br.currentPlayer)
+	}
+}
+
+// reject 生成失败的操作结果
+func (br *BettingRound) reject(message string) ActionResult {
+	return ActionResult{
+		Success: false,
+		Message: message,
+	}
+}
+
+// putChips 从玩家剩余筹码中扣除并计入本轮下注，筹码用完即为全押
+func (br *BettingRound) putChips(playerID int64, chips int) {
+	br.stacks[playerID] -= chips
+	br.playerBets[playerID] += chips
+	if br.stacks[playerID] == 0 {
+		br.allIn[playerID] = true
+	}
+}
+
+// raiseTo 将当前最高下注提高到total
+// 相对最近一次完整下注的增量达到最小加注增量时为完整加注，重新开放加注权；
+// 否则为不足额全押，已操作过的玩家只能跟注或弃牌
+func (br *BettingRound) raiseTo(total int) {
+	previous := br.currentBet
+	br.currentBet = total
+
+	if previous == 0 || total-br.lastFullBet >= br.lastRaise {
+		br.lastRaise = max(br.lastRaise, total-previous)
+		br.lastFullBet = total
+		br.fullRaises++
+	}
+}
+
+// refresh 检查下注轮是否完成，未完成时从from开始找到下一个需要操作的玩家
+func (br *BettingRound) refresh(from int) {
+	if br.isBettingComplete() {
+		br.completed = true
+		br.currentPlayer = -1
+		return
+	}
+
+	from = max(from, 0)
+	for i := 0; i < len(br.players); i++ {
+		index := (from + i) % len(br.players)
+		if br.canAct(br.players[index]) {
+			br.currentPlayer = index
+			return
+		}
+	}
+}
+
+// canAct 检查玩家是否还能操作（未弃牌且未全押）
+func (br *BettingRound) canAct(playerID int64) bool {
+	return !br.folded[playerID] && !br.allIn[playerID]
+}
+
+// isBettingComplete 检查下注轮是否完成
+func (br *BettingRound) isBettingComplete() bool {
+	remaining := 0
+	actors := make([]int64, 0, len(br.players))
+	for _, playerID := range br.players {
+		if br.folded[playerID] {
+			continue
+		}
+		remaining++
+		if !br.allIn[playerID] {
+			actors = append(actors, playerID)
+		}
+	}
+
+	// 只剩一名玩家，或者没有玩家还能操作
+	if remaining <= 1 || len(actors) == 0 {
+		return true
+	}
+
+	// 其他玩家都已全押时，唯一能操作的玩家只需跟上当前下注
+	if len(actors) == 1 {
+		return br.playerBets[actors[0]] >= br.currentBet
+	}
+
+	// 所有能操作的玩家都已操作并且下注金额一致
+	for _, playerID := range actors {
+		if !br.acted[playerID] || br.playerBets[playerID] != br.currentBet {
+			return false
+		}
+	}
+	return true
+}
+
+// CanRaise 检查玩家当前是否可以加注
+// 需要有超过跟注额的筹码、还有其他能操作的对手，并且之后出现过完整加注（或尚未操作过）
+func (br *BettingRound) CanRaise(playerID int64) bool {
+	if !br.canAct(playerID) {
+		return false
+	}
+	if br.stacks[playerID] <= br.currentBet-br.playerBets[playerID] {
+		return false
+	}
+
+	opponents := 0
+	for _, id := range br.players {
+		if id != playerID && br.canAct(id) {
+			opponents++
+		}
+	}
+	if opponents == 0 {
+		return false
+	}
+
+	return !br.acted[playerID] || br.raisesSeen[playerID] < br.fullRaises
+}
+
+// IsCompleted 检查下注轮是否完成
+func (br *BettingRound) IsCompleted() bool {
+	return br.completed
+}
+
+// GetPlayerBets 获取所有玩家的下注
+func (br *BettingRound) GetPlayerBets() map[int64]int {
+	return br.playerBets
+}
+
+// GetCurrentBet 获取当前最高下注
+func (br *BettingRound) GetCurrentBet() int {
+	return br.currentBet
+}
+
+// GetStack 获取玩家本轮的剩余筹码
+func (br *BettingRound) GetStack(playerID int64) int {
+	return br.stacks[playerID]
+}
+
+// GetCallAmount 获取玩家跟注需要补齐的金额
+func (br *BettingRound) GetCallAmount(playerID int64) int {
+	return br.currentBet - br.playerBets[playerID]
+}
+
+// GetMinRaise 获取最小加注额（加注后的总下注，至少比当前下注多出上一次加注的增量）
+func (br *BettingRound) GetMinRaise() int {
+	return br.currentBet + br.lastRaise
+}
+
+// GetMaxRaise 获取玩家最多可以加注到的总下注
+// 无限注为全部筹码；底池限注为当前下注加上跟注后的底池（本轮之前的底池、本轮所有下注和自己的跟注额），不超过全部筹码
+func (br *BettingRound) GetMaxRaise(playerID int64) int {
+	allIn := br.playerBets[playerID] + br.stacks[playerID]
+	if !br.potLimit {
+		return allIn
+	}
+
+	pot := br.potBefore + min(br.GetCallAmount(playerID), br.stacks[playerID])
+	for _, bet := range br.playerBets {
+		pot += bet
+	}
+	return min(br.currentBet+pot, allIn)
+}
@@ -0,0 +1,297 @@
+// 下注轮测试
+// 作用：按操作脚本校验最小加注、不足额全押不重新开放加注和大盲注选择权
+
+package statemachine
+
+import (
+	"testing"
+)
+
+// blindPost 开始下注前登记的盲注
+type blindPost struct {
+	player int64
+	amount int
+}
+
+// bettingStep 下注轮中的一次操作及其是否应被接受
+type bettingStep struct {
+	player int64
+	action PlayerAction
+	amount int
+	reject bool
+}
+
+func TestBettingRound(t *testing.T) {
+	preflop := []blindPost{{1, 5}, {2, 10}}
+
+	tests := []struct {
+		name    string
+		players []int64
+		stacks  map[int64]int
+		blinds  []blindPost
+		steps   []bettingStep
+
+		wantCompleted bool
+		wantCurrent   int64
+		wantMinRaise  int            // 0表示不检查
+		wantCanRaise  map[int64]bool // 下注轮结束前各玩家能否加注
+	}{
+		{
+			name:    "最小加注跟随最大的加注增量",
+			players: []int64{3, 1, 2},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 1000},
+			blinds:  preflop,
+			steps: []bettingStep{
+				{player: 3, action: Raise, amount: 30},
+				{player: 1, action: Raise, amount: 100},
+				{player: 2, action: Raise, amount: 150, reject: true},
+			},
+			wantCurrent:  2,
+			wantMinRaise: 170,
+			wantCanRaise: map[int64]bool{2: true},
+		},
+		{
+			name:    "下注不足大盲注被拒绝",
+			players: []int64{1, 2},
+			stacks:  map[int64]int{1: 1000, 2: 1000},
+			steps: []bettingStep{
+				{player: 1, action: Bet, amount: 5, reject: true},
+				{player: 1, action: Bet, amount: 10},
+			},
+			wantCurrent:  2,
+			wantMinRaise: 20,
+		},
+		{
+			name:    "不足额全押不重新开放加注",
+			players: []int64{1, 2, 3},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 45},
+			steps: []bettingStep{
+				{player: 1, action: Bet, amount: 30},
+				{player: 2, action: Call},
+				{player: 3, action: AllIn},
+				{player: 1, action: Raise, amount: 100, reject: true},
+				{player: 1, action: AllIn, reject: true},
+			},
+			wantCurrent:  1,
+			wantMinRaise: 75,
+			wantCanRaise: map[int64]bool{1: false, 2: false},
+		},
+		{
+			name:    "不足额全押后跟注结束下注轮",
+			players: []int64{1, 2, 3},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 45},
+			steps: []bettingStep{
+				{player: 1, action: Bet, amount: 30},
+				{player: 2, action: Call},
+				{player: 3, action: AllIn},
+				{player: 1, action: Call},
+				{player: 2, action: Call},
+			},
+			wantCompleted: true,
+			wantCurrent:   -1,
+		},
+		{
+			name:    "尚未操作的玩家可以在不足额全押后加注",
+			players: []int64{1, 3, 2},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 45},
+			steps: []bettingStep{
+				{player: 1, action: Bet, amount: 30},
+				{player: 3, action: AllIn},
+				{player: 2, action: Raise, amount: 70, reject: true},
+				{player: 2, action: Raise, amount: 75},
+			},
+			wantCurrent:  1,
+			wantMinRaise: 105,
+			wantCanRaise: map[int64]bool{1: true},
+		},
+		{
+			name:    "累计达到完整加注的全押重新开放加注",
+			players: []int64{1, 2, 3, 4},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 45, 4: 65},
+			steps: []bettingStep{
+				{player: 1, action: Bet, amount: 30},
+				{player: 2, action: Call},
+				{player: 3, action: AllIn},
+				{player: 4, action: AllIn},
+			},
+			wantCurrent:  1,
+			wantMinRaise: 95,
+			wantCanRaise: map[int64]bool{1: true, 2: true},
+		},
+		{
+			name:    "大盲注在跟注后保留选择权",
+			players: []int64{3, 1, 2},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 1000},
+			blinds:  preflop,
+			steps: []bettingStep{
+				{player: 3, action: Call},
+				{player: 1, action: Call},
+			},
+			wantCurrent:  2,
+			wantMinRaise: 20,
+			wantCanRaise: map[int64]bool{2: true},
+		},
+		{
+			name:    "大盲注过牌结束下注轮",
+			players: []int64{3, 1, 2},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 1000},
+			blinds:  preflop,
+			steps: []bettingStep{
+				{player: 3, action: Call},
+				{player: 1, action: Call},
+				{player: 2, action: Check},
+			},
+			wantCompleted: true,
+			wantCurrent:   -1,
+		},
+		{
+			name:    "大盲注行使选择权加注",
+			players: []int64{3, 1, 2},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 1000},
+			blinds:  preflop,
+			steps: []bettingStep{
+				{player: 3, action: Call},
+				{player: 1, action: Call},
+				{player: 2, action: Raise, amount: 30},
+			},
+			wantCurrent:  3,
+			wantMinRaise: 50,
+			wantCanRaise: map[int64]bool{3: true},
+		},
+		{
+			name:    "大盲注不足额时其他玩家仍需跟注完整的大盲注",
+			players: []int64{1, 2},
+			stacks:  map[int64]int{1: 1000, 2: 6},
+			blinds:  preflop,
+			steps: []bettingStep{
+				{player: 1, action: Check, reject: true},
+				{player: 1, action: Call},
+			},
+			wantCompleted: true,
+			wantCurrent:   -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := NewBettingRound(BettingConfig{Players: tt.players, Stacks: tt.stacks, BigBlind: 10})
+			for _, blind := range tt.blinds {
+				br.PostBlind(blind.player, blind.amount)
+			}
+
+			for i, step := range tt.steps {
+				result := br.ProcessAction(step.player, step.action, step.amount)
+				if result.Success == step.reject {
+					t.Fatalf("第%d步 玩家%d %s %d: Success=%v（%s）", i+1, step.player, step.action.Key(), step.amount, result.Success, result.Message)
+				}
+			}
+
+			if got := br.IsCompleted(); got != tt.wantCompleted {
+				t.Errorf("IsCompleted() = %v，期望 %v", got, tt.wantCompleted)
+			}
+			if got := br.GetCurrentPlayer(); got != tt.wantCurrent {
+				t.Errorf("GetCurrentPlayer() = %d，期望 %d", got, tt.wantCurrent)
+			}
+			if tt.wantMinRaise != 0 {
+				if got := br.GetMinRaise(); got != tt.wantMinRaise {
+					t.Errorf("GetMinRaise() = %d，期望 %d", got, tt.wantMinRaise)
+				}
+			}
+			for playerID, want := range tt.wantCanRaise {
+				if got := br.CanRaise(playerID); got != want {
+					t.Errorf("CanRaise(%d) = %v，期望 %v", playerID, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPotLimitMaxRaise(t *testing.T) {
+	preflop := []blindPost{{1, 5}, {2, 10}}
+
+	tests := []struct {
+		name      string
+		players   []int64
+		stacks    map[int64]int
+		potBefore int
+		blinds    []blindPost
+		steps     []bettingStep
+		player    int64
+		want      int
+	}{
+		{
+			name:    "翻牌前首个行动者最多加注到35（大盲注加上跟注后的底池25）",
+			players: []int64{3, 1, 2},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 1000},
+			blinds:  preflop,
+			player:  3,
+			want:    35,
+		},
+		{
+			name:    "加注到底池后小盲注最多加注到115",
+			players: []int64{3, 1, 2},
+			stacks:  map[int64]int{1: 1000, 2: 1000, 3: 1000},
+			blinds:  preflop,
+			steps: []bettingStep{
+				{player: 3, action: Raise, amount: 36, reject: true},
+				{player: 3, action: Raise, amount: 35},
+			},
+			player: 1,
+			want:   115,
+		},
+		{
+			name:      "翻牌后首个下注最多为底池",
+			players:   []int64{1, 2},
+			stacks:    map[int64]int{1: 1000, 2: 1000},
+			potBefore: 100,
+			player:    1,
+			want:      100,
+		},
+		{
+			name:      "下注后加注不超过当前下注加上跟注后的底池",
+			players:   []int64{1, 2},
+			stacks:    map[int64]int{1: 1000, 2: 1000},
+			potBefore: 100,
+			steps: []bettingStep{
+				{player: 1, action: Bet, amount: 150, reject: true},
+				{player: 1, action: Bet, amount: 50},
+			},
+			player: 2,
+			want:   250, // 50 + (100 + 50 + 50)
+		},
+		{
+			name:      "筹码不足底池时最多全押",
+			players:   []int64{1, 2},
+			stacks:    map[int64]int{1: 60, 2: 1000},
+			potBefore: 100,
+			player:    1,
+			want:      60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := NewBettingRound(BettingConfig{
+				Players:   tt.players,
+				Stacks:    tt.stacks,
+				BigBlind:  10,
+				PotLimit:  true,
+				PotBefore: tt.potBefore,
+			})
+			for _, blind := range tt.blinds {
+				br.PostBlind(blind.player, blind.amount)
+			}
+
+			for i, step := range tt.steps {
+				result := br.ProcessAction(step.player, step.action, step.amount)
+				if result.Success == step.reject {
+					t.Fatalf("第%d步 玩家%d %s %d: Success=%v（%s）", i+1, step.player, step.action.Key(), step.amount, result.Success, result.Message)
+				}
+			}
+
+			if got := br.GetMaxRaise(tt.player); got != tt.want {
+				t.Errorf("GetMaxRaise(%d) = %d，期望 %d", tt.player, got, tt.want)
+			}
+		})
+	}
+}
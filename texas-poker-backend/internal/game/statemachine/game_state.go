@@ -43,6 +43,28 @@ func (gs GameState) String() string {
 	}
 }
 
+// Key 游戏状态的英文标识（与前端约定一致）
+func (gs GameState) Key() string {
+	switch gs {
+	case WaitingForPlayers:
+		return "waiting"
+	case PreFlop:
+		return "preflop"
+	case Flop:
+		return "flop"
+	case Turn:
+		return "turn"
+	case River:
+		return "river"
+	case Showdown:
+		return "showdown"
+	case GameEnd:
+		return "ended"
+	default:
+		return "unknown"
+	}
+}
+
 // GameEvent 游戏事件枚举
 type GameEvent int
 
@@ -55,6 +77,7 @@ const (
 	NextRound                        // 下一轮
 	PlayerLeft                       // 玩家离开
 	GameReset                        // 游戏重置
+	AllOthersFolded                  // 其他玩家全部弃牌
 )
 
 // String 游戏事件转字符串
@@ -76,6 +99,8 @@ func (ge GameEvent) String() string {
 		return "玩家离开"
 	case GameReset:
 		return "游戏重置"
+	case AllOthersFolded:
+		return "其他玩家全部弃牌"
 	default:
 		return "未知事件"
 	}
@@ -112,24 +137,28 @@ func createTransitions() StateTransition {
 		PreFlop: {
 			BettingComplete: Flop,
 			PlayerLeft:      GameEnd,
+			AllOthersFolded: GameEnd,
 			GameReset:       WaitingForPlayers,
 		},
 		Flop: {
 			BettingComplete: Turn,
 			ShowCards:       Showdown,
 			PlayerLeft:      GameEnd,
+			AllOthersFolded: GameEnd,
 			GameReset:       WaitingForPlayers,
 		},
 		Turn: {
 			BettingComplete: River,
 			ShowCards:       Showdown,
 			PlayerLeft:      GameEnd,
+			AllOthersFolded: GameEnd,
 			GameReset:       WaitingForPlayers,
 		},
 		River: {
 			BettingComplete: Showdown,
 			ShowCards:       Showdown,
 			PlayerLeft:      GameEnd,
+			AllOthersFolded: GameEnd,
 			GameReset:       WaitingForPlayers,
 		},
 		Showdown: {
@@ -234,196 +263,60 @@ func (pa PlayerAction) String() string {
 	}
 }
 
-// ActionRequest 玩家操作请求
-type ActionRequest struct {
-	PlayerID int64        `json:"player_id"`
-	Action   PlayerAction `json:"action"`
-	Amount   int          `json:"amount"`
-	Timestamp time.Time   `json:"timestamp"`
-}
-
-// ActionResult 操作结果
-type ActionResult struct {
-	Success   bool   `json:"success"`
-	Message   string `json:"message"`
-	NextEvent GameEvent `json:"next_event,omitempty"`
-}
-
-// BettingRound 下注轮管理
-type BettingRound struct {
-	currentBet    int               // 当前最高下注
-	players       []int64           // 参与下注的玩家ID列表
-	currentPlayer int               // 当前轮到的玩家索引
-	playerBets    map[int64]int     // 每个玩家的下注金额
-	playerActions map[int64]PlayerAction // 每个玩家的最后操作
-	completed     bool              // 下注轮是否完成
-}
-
-// NewBettingRound 创建新的下注轮
-func NewBettingRound(players []int64) *BettingRound {
-	return &BettingRound{
-		currentBet:    0,
-		players:       players,
-		currentPlayer: 0,
-		playerBets:    make(map[int64]int),
-		playerActions: make(map[int64]PlayerAction),
-		completed:     false,
-	}
-}
-
-// GetCurrentPlayer 获取当前应该操作的玩家
-func (br *BettingRound) GetCurrentPlayer() int64 {
-	if br.currentPlayer >= len(br.players) {
-		return -1 // 无效玩家
-	}
-	return br.players[br.currentPlayer]
-}
-
-// ProcessAction 处理玩家操作
-func (br *BettingRound) ProcessAction(playerID int64, action PlayerAction, amount int) ActionResult {
-	// 验证是否为当前玩家
-	if playerID != br.GetCurrentPlayer() {
-		return ActionResult{
-			Success: false,
-			Message: "不是您的操作轮次",
-		}
-	}
-	
-	// 处理不同的操作
-	switch action {
+// Key 玩家操作的英文标识（与前端约定一致）
+func (pa PlayerAction) Key() string {
+	switch pa {
 	case Fold:
-		// 移除弃牌玩家
-		br.removePlayer(playerID)
-		br.playerActions[playerID] = Fold
-		
+		return "fold"
 	case Call:
-		// 跟注到当前最高金额
-		br.playerBets[playerID] = br.currentBet
-		br.playerActions[playerID] = Call
-		br.nextPlayer()
-		
+		return "call"
 	case Raise:
-		// 加注
-		if amount <= br.currentBet {
-			return ActionResult{
-				Success: false,
-				Message: "加注金额必须大于当前下注",
-			}
-		}
-		br.currentBet = amount
-		br.playerBets[playerID] = amount
-		br.playerActions[playerID] = Raise
-		br.nextPlayer()
-		
+		return "raise"
 	case Check:
-		// 过牌（只有在没有人下注时才能过牌）
-		if br.currentBet > 0 {
-			return ActionResult{
-				Success: false,
-				Message: "已有人下注，无法过牌",
-			}
-		}
-		br.playerActions[playerID] = Check
-		br.nextPlayer()
-		
+		return "check"
 	case Bet:
-		// 下注（只有在没有人下注时才能下注）
-		if br.currentBet > 0 {
-			return ActionResult{
-				Success: false,
-				Message: "已有人下注，请选择跟注或加注",
-			}
-		}
-		br.currentBet = amount
-		br.playerBets[playerID] = amount
-		br.playerActions[playerID] = Bet
-		br.nextPlayer()
-		
+		return "bet"
 	case AllIn:
-		// 全押
-		br.currentBet = amount // 这里应该是玩家的全部筹码
-		br.playerBets[playerID] = amount
-		br.playerActions[playerID] = AllIn
-		br.nextPlayer()
-		
+		return "all_in"
 	default:
-		return ActionResult{
-			Success: false,
-			Message: "无效的操作类型",
-		}
-	}
-	
-	// 检查下注轮是否完成
-	if br.isBettingComplete() {
-		br.completed = true
-		return ActionResult{
-			Success:   true,
-			Message:   fmt.Sprintf("玩家 %d %s 成功，下注轮结束", playerID, action.String()),
-			NextEvent: BettingComplete,
-		}
+		return "unknown"
 	}
-	
-	return ActionResult{
-		Success: true,
-		Message: fmt.Sprintf("玩家 %d %s 成功", playerID, action.String()),
-	}
-}
-
-// removePlayer 从下注轮中移除玩家
-func (br *BettingRound) removePlayer(playerID int64) {
-	for i, id := range br.players {
-		if id == playerID {
-			br.players = append(br.players[:i], br.players[i+1:]...)
-			if br.currentPlayer > i {
-				br.currentPlayer--
-			}
-			break
-		}
-	}
-	
-	// 如果当前玩家索引超出范围，回到开始
-	if br.currentPlayer >= len(br.players) {
-		br.currentPlayer = 0
-	}
-}
-
-// nextPlayer 切换到下一个玩家
-func (br *BettingRound) nextPlayer() {
-	br.currentPlayer = (br.currentPlayer + 1) % len(br.players)
 }
 
-// isBettingComplete 检查下注轮是否完成
-func (br *BettingRound) isBettingComplete() bool {
-	if len(br.players) <= 1 {
-		return true // 只剩一个或没有玩家
-	}
-	
-	// 检查所有活跃玩家是否都已经操作并且下注金额一致
-	activePlayers := 0
-	for _, playerID := range br.players {
-		if action, exists := br.playerActions[playerID]; exists && action != Fold {
-			activePlayers++
-			// 检查是否所有玩家的下注都等于当前最高下注
-			if br.playerBets[playerID] != br.currentBet {
-				return false
-			}
-		}
+// ParsePlayerAction 从英文标识解析玩家操作
+func ParsePlayerAction(s string) (PlayerAction, error) {
+	switch s {
+	case "fold":
+		return Fold, nil
+	case "call":
+		return Call, nil
+	case "raise":
+		return Raise, nil
+	case "check":
+		return Check, nil
+	case "bet":
+		return Bet, nil
+	case "all_in", "allin":
+		return AllIn, nil
+	default:
+		return 0, fmt.Errorf("无效的操作类型: %s", s)
 	}
-	
-	return activePlayers > 0
 }
 
-// IsCompleted 检查下注轮是否完成
-func (br *BettingRound) IsCompleted() bool {
-	return br.completed
+// ActionRequest 玩家操作请求
+type ActionRequest struct {
+	PlayerID int64        `json:"player_id"`
+	Action   PlayerAction `json:"action"`
+	Amount   int          `json:"amount"`
+	Timestamp time.Time   `json:"timestamp"`
 }
 
-// GetPlayerBets 获取所有玩家的下注
-func (br *BettingRound) GetPlayerBets() map[int64]int {
-	return br.playerBets
+// ActionResult 操作结果
+type ActionResult struct {
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+	Action    PlayerAction `json:"action"`   // 实际执行的操作（下注/加注会按当前下注情况规范化）
+	Amount    int    `json:"amount"`         // 本次操作投入的筹码
+	AllIn     bool   `json:"all_in"`         // 本次操作后玩家是否已全押
+	NextEvent GameEvent `json:"next_event,omitempty"`
 }
-
-// GetCurrentBet 获取当前最高下注
-func (br *BettingRound) GetCurrentBet() int {
-	return br.currentBet
-} 
\ No newline at end of file
@@ -0,0 +1,285 @@
+// 管理员处理器
+// 作用：处理管理员登录、用户管理、房间监控和统计数据等后台HTTP请求
+
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"texas-poker-backend/internal/models"
+	"texas-poker-backend/internal/utils"
+)
+
+// 用户列表分页参数
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// AdminLogin 管理员登录
+func (h *Handler) AdminLogin(c *gin.Context) {
+	var req models.AdminLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数无效",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	admin, err := models.GetAdminByUsername(h.db, req.Username)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "用户名或密码错误",
+		})
+		return
+	}
+
+	// 检查账号状态
+	if admin.Status != "active" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "管理员账号已被停用",
+		})
+		return
+	}
+
+	// 验证密码
+	if !utils.CheckPassword(req.Password, admin.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "用户名或密码错误",
+		})
+		return
+	}
+
+	// 使用管理员密钥签发token
+	token, err := utils.GenerateToken(admin.ID, admin.Username, "admin", h.config.AdminSecret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Token生成失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "登录成功",
+		"admin":   admin.ToResponse(),
+		"token":   token,
+	})
+}
+
+// GetUsers 分页查询用户列表，支持按关键字和状态筛选
+func (h *Handler) GetUsers(c *gin.Context) {
+	filter := models.UserFilter{
+		Keyword:  c.Query("keyword"),
+		Status:   c.Query("status"),
+		Page:     queryInt(c, "page", 1),
+		PageSize: queryInt(c, "page_size", defaultPageSize),
+	}
+
+	if filter.Status != "" && filter.Status != "active" && filter.Status != "disabled" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的账号状态",
+		})
+		return
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > maxPageSize {
+		filter.PageSize = defaultPageSize
+	}
+
+	users, total, err := models.SearchUsers(h.db, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "查询用户失败",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	responses := make([]*models.UserResponse, 0, len(users))
+	for _, user := range users {
+		responses = append(responses, user.ToResponse())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":     responses,
+		"total":     total,
+		"page":      filter.Page,
+		"page_size": filter.PageSize,
+	})
+}
+
+// UpdateUser 启用/禁用用户或调整用户筹码，所有修改都会写入审计日志
+// 用户在房间中或还有未结算的牌局时不能调整筹码
+func (h *Handler) UpdateUser(c *gin.Context) {
+	adminID, exists := c.Get("admin_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "管理员未认证",
+		})
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的用户ID",
+		})
+		return
+	}
+
+	var req models.AdminUpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数无效",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if req.Status == "" && req.ChipsDelta == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "没有需要更新的内容",
+		})
+		return
+	}
+
+	// 入座玩家的筹码由房间维护并在离开或结算时写回，此时修改数据库中的筹码会被覆盖或导致结算失败
+	if req.ChipsDelta != 0 {
+		unlock := h.rooms.LockUser(userID)
+		defer unlock()
+
+		if _, seated := h.rooms.FindPlayerRoom(userID); seated || h.rooms.HasUnsettledChips(userID) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "用户正在房间中或有未结算的牌局，请在其离开房间并完成结算后再调整筹码",
+			})
+			return
+		}
+	}
+
+	if err := models.AdminUpdateUser(h.db, adminID.(int64), userID, &req); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "用户不存在",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	user, err := models.GetUserByID(h.db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取用户信息失败",
+		})
+		return
+	}
+
+	auditLogs, err := models.GetAuditLogsByUser(h.db, userID, 10)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取审计日志失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "更新成功",
+		"user":       user.ToResponse(),
+		"audit_logs": auditLogs,
+	})
+}
+
+// GetRoomsAdmin 获取所有房间（包括已关闭的房间）及其实时状态
+func (h *Handler) GetRoomsAdmin(c *gin.Context) {
+	records, err := models.GetAllRooms(h.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "获取房间列表失败",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rooms := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		if record.Status == "closed" {
+			rooms = append(rooms, map[string]interface{}{
+				"id":              record.ID,
+				"name":            record.Name,
+				"chip_level":      record.ChipLevel,
+				"min_chips":       record.MinChips,
+				"max_players":     record.MaxPlayers,
+				"current_players": 0,
+				"is_private":      record.IsPrivate,
+				"status":          record.Status,
+				"small_blind":     record.SmallBlind,
+				"big_blind":       record.BigBlind,
+				"created_at":      record.CreatedAt,
+			})
+			continue
+		}
+		rooms = append(rooms, h.liveRoom(record).GetRoomInfo())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rooms": rooms,
+	})
+}
+
+// GetStats 获取后台统计数据（字段与管理后台仪表盘保持一致）
+func (h *Handler) GetStats(c *gin.Context) {
+	totalGames, err := models.CountGames(h.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "获取统计数据失败",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	chipsCirculation, err := models.SumUserChips(h.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "获取统计数据失败",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// 有玩家入座的房间视为活跃房间
+	activeRooms := 0
+	for _, liveRoom := range h.rooms.List() {
+		if liveRoom.PlayerCount() > 0 {
+			activeRooms++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats": gin.H{
+			"onlineUsers":      len(h.wsManager.GetConnectedUsers()),
+			"activeRooms":      activeRooms,
+			"totalGames":       totalGames,
+			"chipsCirculation": chipsCirculation,
+		},
+	})
+}
+
+// queryInt 读取整数查询参数，缺失或无效时返回默认值
+func queryInt(c *gin.Context, key string, defaultValue int) int {
+	value, err := strconv.Atoi(c.Query(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
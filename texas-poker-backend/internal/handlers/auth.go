@@ -11,6 +11,7 @@ import (
 	"github.com/go-redis/redis/v8"
 
 	"texas-poker-backend/internal/config"
+	"texas-poker-backend/internal/game/room"
 	"texas-poker-backend/internal/models"
 	"texas-poker-backend/internal/utils"
 	"texas-poker-backend/internal/websocket"
@@ -21,17 +22,25 @@ type Handler struct {
 	db        *sql.DB
 	redis     *redis.Client
 	wsManager *websocket.Manager
+	rooms     *room.Manager
 	config    *config.Config
+	unsaved   unsavedHands // 重试用尽仍未持久化的牌局
 }
 
 // New 创建新的处理器实例
 func New(db *sql.DB, redis *redis.Client, wsManager *websocket.Manager) *Handler {
-	return &Handler{
+	h := &Handler{
 		db:        db,
 		redis:     redis,
 		wsManager: wsManager,
+		rooms:     room.NewManager(),
 		config:    config.Load(),
 	}
+
+	// 游戏消息由处理器分发到房间引擎
+	wsManager.SetMessageHandler(h.handleGameMessage)
+
+	return h
 }
 
 // Register 用户注册
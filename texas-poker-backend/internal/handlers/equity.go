@@ -0,0 +1,89 @@
+// 胜率计算处理器
+// 作用：提供已知底牌在部分或空白公共牌下的胜率计算接口
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/poker/equity"
+	"texas-poker-backend/internal/models"
+)
+
+// CalculateEquity 计算多手底牌的胜/平/负概率
+func (h *Handler) CalculateEquity(c *gin.Context) {
+	var req models.EquityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数无效",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	gameType, err := poker.ParseGameType(req.GameType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	calc := equity.Request{GameType: gameType, Iterations: req.Iterations}
+	for _, hand := range req.Hands {
+		cards, parseErr := parseCards(hand)
+		if parseErr != nil {
+			err = parseErr
+			break
+		}
+		calc.Hands = append(calc.Hands, cards)
+	}
+	if err == nil {
+		calc.Board, err = parseCards(req.Board)
+	}
+	if err == nil {
+		calc.Dead, err = parseCards(req.Dead)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "无效的扑克牌",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := equity.Calculate(calc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "无法计算胜率",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"equity": result,
+	})
+}
+
+// parseCards 解析一组牌面记法（每个元素可以是一张或连写的多张牌）
+func parseCards(values []string) ([]poker.Card, error) {
+	cards := make([]poker.Card, 0, len(values))
+	for _, value := range values {
+		parsed, err := poker.ParseCards(value)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, parsed...)
+	}
+	return cards, nil
+}
@@ -0,0 +1,87 @@
+// 公平性验证处理器
+// 作用：公开的牌局复核接口，根据已揭示的服务器种子和客户端种子重新推导牌堆并核对发出的牌
+
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"texas-poker-backend/internal/game/room"
+	"texas-poker-backend/internal/models"
+)
+
+// VerifyGame 复核已结束牌局的洗牌和发牌（公开接口）
+// 牌局结束后种子已经公开，任何人都能推导出整副牌堆，所以这里直接返回牌堆顺序和每个座位的底牌
+func (h *Handler) VerifyGame(c *gin.Context) {
+	gameID, record, ok := h.loadVerifiableGame(c)
+	if !ok {
+		return
+	}
+
+	verification, err := room.VerifyHand(record)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// 只返回复核需要的发牌信息，不公开用户名和筹码
+	dealt := make([]gin.H, 0, len(record.Players))
+	for _, player := range record.Players {
+		dealt = append(dealt, gin.H{
+			"player_id": player.ID,
+			"position":  player.Position,
+			"cards":     player.Cards,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"game_id":      gameID,
+		"board":        record.Board,
+		"players":      dealt,
+		"verification": verification,
+	})
+}
+
+// loadVerifiableGame 读取路径参数id指定的牌局记录，失败时直接返回错误响应
+func (h *Handler) loadVerifiableGame(c *gin.Context) (int64, *room.HandRecord, bool) {
+	gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的牌局ID",
+		})
+		return 0, nil, false
+	}
+
+	game, err := models.GetGameByID(h.db, gameID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "牌局不存在",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "获取牌局记录失败",
+				"details": err.Error(),
+			})
+		}
+		return 0, nil, false
+	}
+
+	var record room.HandRecord
+	if err := json.Unmarshal(game.GameLog, &record); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "牌局记录格式无效",
+			"details": err.Error(),
+		})
+		return 0, nil, false
+	}
+	return game.ID, &record, true
+}
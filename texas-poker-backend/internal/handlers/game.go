@@ -0,0 +1,297 @@
+// 游戏消息分发器
+// 作用：将WebSocket客户端的游戏消息（加入/离开房间、玩家操作等）映射到房间引擎，并推送状态更新
+
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"texas-poker-backend/internal/game/room"
+	"texas-poker-backend/internal/game/statemachine"
+	"texas-poker-backend/internal/models"
+	"texas-poker-backend/internal/websocket"
+)
+
+// WebSocket错误码（error帧的payload.code字段）
+const (
+	wsErrInvalidMessage = "invalid_message" // 消息格式或参数错误
+	wsErrRoomNotFound   = "room_not_found"  // 房间不存在
+	wsErrJoinFailed     = "join_failed"     // 加入房间失败
+	wsErrNotInRoom      = "not_in_room"     // 玩家不在任何房间中
+	wsErrActionFailed   = "action_failed"   // 游戏操作被拒绝
+	wsErrStartFailed    = "start_failed"    // 开始游戏失败
+	wsErrUnknownType    = "unknown_type"    // 未知的消息类型
+	wsErrInternal       = "internal_error"  // 服务器内部错误
+)
+
+// nextHandDelay 开启自动开局的房间一局结束后到开始下一局的间隔（留给客户端展示结算）
+const nextHandDelay = 5 * time.Second
+
+// handleGameMessage 分发客户端游戏消息
+func (h *Handler) handleGameMessage(client *websocket.Client, msg websocket.ClientMessage) {
+	switch msg.Type {
+	case "join_room":
+		h.wsJoinRoom(client, msg)
+	case "leave_room":
+		h.wsLeaveRoom(client)
+	case "start_game":
+		h.wsStartGame(client)
+	case "player_action":
+		h.wsPlayerAction(client, msg)
+	case "set_client_seed":
+		h.wsSetClientSeed(client, msg)
+	default:
+		sendError(client, wsErrUnknownType, fmt.Sprintf("未知的消息类型: %s", msg.Type))
+	}
+}
+
+// wsJoinRoom 处理加入房间消息
+func (h *Handler) wsJoinRoom(client *websocket.Client, msg websocket.ClientMessage) {
+	roomID, err := parseRoomID(msg.RoomID)
+	if err != nil {
+		sendError(client, wsErrInvalidMessage, "无效的房间ID")
+		return
+	}
+
+	record, err := models.GetRoomByID(h.db, roomID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			sendError(client, wsErrRoomNotFound, "房间不存在")
+		} else {
+			sendError(client, wsErrInternal, "获取房间信息失败")
+		}
+		return
+	}
+
+	// 观战者只订阅房间消息，不入座
+	if msg.Spectate {
+		liveRoom, err := h.spectateRoom(record, msg.Password)
+		if err != nil {
+			sendError(client, wsErrJoinFailed, err.Error())
+			return
+		}
+		h.wsManager.Subscribe(client, liveRoom.ID)
+		client.SendMessage(websocket.Message{
+			Type:    "room_update",
+			Payload: liveRoom.GetRoomInfo(),
+		})
+		client.SendMessage(websocket.Message{
+			Type:    "game_state_update",
+			Payload: liveRoom.SnapshotFor(client.UserID),
+		})
+		return
+	}
+
+	liveRoom, err := h.joinRoom(client.UserID, record, msg.Password)
+	if err != nil {
+		sendError(client, wsErrJoinFailed, err.Error())
+		return
+	}
+
+	h.wsManager.Subscribe(client, liveRoom.ID)
+	h.afterJoin(liveRoom)
+}
+
+// wsLeaveRoom 处理离开房间消息（入座玩家离座，观战者取消订阅）
+func (h *Handler) wsLeaveRoom(client *websocket.Client) {
+	liveRoom, inRoom := h.rooms.FindPlayerRoom(client.UserID)
+	if !inRoom {
+		subscribed := h.wsManager.SubscribedRooms(client)
+		if len(subscribed) == 0 {
+			sendError(client, wsErrNotInRoom, "您不在任何房间中")
+			return
+		}
+		for _, roomID := range subscribed {
+			h.wsManager.Unsubscribe(client, roomID)
+		}
+		return
+	}
+
+	if err := liveRoom.RemovePlayer(client.UserID); err != nil {
+		sendError(client, wsErrActionFailed, err.Error())
+		return
+	}
+
+	h.afterLeave(liveRoom)
+	h.wsManager.Unsubscribe(client, liveRoom.ID)
+}
+
+// wsStartGame 处理手动开始游戏消息
+func (h *Handler) wsStartGame(client *websocket.Client) {
+	liveRoom, inRoom := h.rooms.FindPlayerRoom(client.UserID)
+	if !inRoom {
+		sendError(client, wsErrNotInRoom, "您不在任何房间中")
+		return
+	}
+
+	if err := liveRoom.StartGame(); err != nil {
+		sendError(client, wsErrStartFailed, err.Error())
+		return
+	}
+
+	h.pushRoomUpdate(liveRoom)
+	h.pushGameState(liveRoom)
+}
+
+// wsSetClientSeed 处理设置客户端种子消息（从下一局开始参与洗牌）
+func (h *Handler) wsSetClientSeed(client *websocket.Client, msg websocket.ClientMessage) {
+	liveRoom, inRoom := h.rooms.FindPlayerRoom(client.UserID)
+	if !inRoom {
+		sendError(client, wsErrNotInRoom, "您不在任何房间中")
+		return
+	}
+
+	if err := liveRoom.SetClientSeed(client.UserID, msg.Seed); err != nil {
+		sendError(client, wsErrInvalidMessage, err.Error())
+		return
+	}
+
+	client.SendMessage(websocket.Message{
+		Type: "client_seed_set",
+		Payload: map[string]interface{}{
+			"seed":           msg.Seed,
+			"next_seed_hash": liveRoom.NextSeedHash(),
+		},
+	})
+}
+
+// wsPlayerAction 处理玩家操作消息
+func (h *Handler) wsPlayerAction(client *websocket.Client, msg websocket.ClientMessage) {
+	action, err := statemachine.ParsePlayerAction(strings.ToLower(msg.Action))
+	if err != nil {
+		sendError(client, wsErrInvalidMessage, err.Error())
+		return
+	}
+
+	liveRoom, inRoom := h.rooms.FindPlayerRoom(client.UserID)
+	if !inRoom {
+		sendError(client, wsErrNotInRoom, "您不在任何房间中")
+		return
+	}
+
+	// 操作本身、随后的发牌和结算以及最新的游戏状态都由房间事件推送
+	result, err := liveRoom.ProcessPlayerAction(client.UserID, action, msg.Amount)
+	if err != nil {
+		sendError(client, wsErrActionFailed, err.Error())
+		return
+	}
+	if !result.Success {
+		sendError(client, wsErrActionFailed, result.Message)
+	}
+}
+
+// handleRoomEvent 将房间引擎产生的牌局事件推送给房间订阅者
+// 所有事件都按观察者逐个直接发送，与游戏状态推送走同一条路径，客户端收到的顺序与产生顺序一致；公开底牌的事件观战者看不到底牌
+// 轮到新玩家操作（包括超时自动操作之后）或一局结束时推送最新游戏状态，开启自动开局的房间一局结束后安排下一局
+func (h *Handler) handleRoomEvent(liveRoom *room.Room, event room.Event) {
+	if event.RevealsHoleCards() {
+		h.wsManager.SendToRoomEach(liveRoom.ID, func(userID int64) websocket.Message {
+			_, seated := liveRoom.GetPlayer(userID)
+			return websocket.Message{Type: event.Type, Payload: event.PayloadFor(seated)}
+		})
+	} else {
+		h.sendToRoom(liveRoom, event.Type, event.Payload())
+	}
+
+	switch event.Type {
+	case room.EventTurnStarted, room.EventStreetChanged:
+		// 每条街推送各自视角的快照，玩家可以看到自己当前的牌型（全押自动发牌时没有turn_started）
+		h.pushGameState(liveRoom)
+	case room.EventHandEnded:
+		// 持久化在后台进行，数据库缓慢时不阻塞事件推送
+		go h.saveHand(liveRoom, event.Hand)
+		h.pushGameState(liveRoom)
+		if liveRoom.AutoStart {
+			liveRoom.ScheduleNextHand(nextHandDelay, func() {
+				h.tryStartGame(liveRoom)
+				h.pushRoomUpdate(liveRoom)
+				h.pushGameState(liveRoom)
+			})
+		}
+	}
+}
+
+// afterJoin 玩家入座后推送房间信息，开启自动开局的房间满足条件时开始游戏
+func (h *Handler) afterJoin(liveRoom *room.Room) {
+	h.pushRoomUpdate(liveRoom)
+	if liveRoom.AutoStart {
+		h.tryStartGame(liveRoom)
+	}
+	h.pushGameState(liveRoom)
+}
+
+// afterLeave 玩家离座后推送房间信息
+func (h *Handler) afterLeave(liveRoom *room.Room) {
+	h.pushRoomUpdate(liveRoom)
+	h.pushGameState(liveRoom)
+}
+
+// tryStartGame 房间满足条件时开始游戏
+func (h *Handler) tryStartGame(liveRoom *room.Room) {
+	if !liveRoom.CanStart() {
+		return
+	}
+	if err := liveRoom.StartGame(); err != nil {
+		log.Printf("Failed to start game in room %d: %v", liveRoom.ID, err)
+	}
+}
+
+// pushRoomUpdate 向房间订阅者推送房间信息
+func (h *Handler) pushRoomUpdate(liveRoom *room.Room) {
+	h.sendToRoom(liveRoom, "room_update", liveRoom.GetRoomInfo())
+}
+
+// sendToRoom 向房间的每个订阅者直接发送同一条消息
+// 房间消息不经过Hub的广播通道，否则会与直接发送的牌局事件和游戏状态乱序
+func (h *Handler) sendToRoom(liveRoom *room.Room, messageType string, payload interface{}) {
+	message := websocket.Message{Type: messageType, Payload: payload}
+	h.wsManager.SendToRoomEach(liveRoom.ID, func(int64) websocket.Message {
+		return message
+	})
+}
+
+// pushGameState 向房间订阅者推送各自视角的游戏状态（不会泄露对手底牌）
+func (h *Handler) pushGameState(liveRoom *room.Room) {
+	h.wsManager.SendToRoomEach(liveRoom.ID, func(userID int64) websocket.Message {
+		return websocket.Message{
+			Type:    "game_state_update",
+			Payload: liveRoom.SnapshotFor(userID),
+		}
+	})
+}
+
+// sendError 向客户端发送typed error帧
+func sendError(client *websocket.Client, code, message string) {
+	client.SendMessage(websocket.Message{
+		Type: "error",
+		Payload: map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// parseRoomID 解析房间ID（兼容数字和字符串两种格式）
+func parseRoomID(raw json.RawMessage) (int64, error) {
+	if len(raw) == 0 {
+		return 0, errors.New("missing room_id")
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strconv.ParseInt(text, 10, 64)
+	}
+
+	var id int64
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
@@ -0,0 +1,288 @@
+// 牌局历史处理器
+// 作用：按日期范围导出当前用户参与过的牌局历史（PokerStars文本格式或OHH JSON），供HM3、PokerTracker等软件导入；
+// 管理员可以导入OHH牌局，经房间引擎重放核对结算后写入games表
+
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"texas-poker-backend/internal/game/room"
+	"texas-poker-backend/internal/models"
+)
+
+const (
+	historyDateLayout = "2006-01-02"
+	maxHistoryDays    = 366  // 单次导出的最大天数
+	maxHistoryHands   = 5000 // 单次导出的最多局数
+)
+
+// historyHand 导出的一局：牌局编号（games.id）和牌局记录
+type historyHand struct {
+	id     int64
+	record *room.HandRecord
+}
+
+// ExportPokerStars 下载当前用户在日期范围内的牌局历史（PokerStars格式）
+// 查询参数from、to为 YYYY-MM-DD（包含两端），缺省时导出最近30天；范围内的牌局超过单次导出上限时返回错误
+func (h *Handler) ExportPokerStars(c *gin.Context) {
+	userID, hands, period, ok := h.loadHistory(c)
+	if !ok {
+		return
+	}
+
+	histories := make([]string, 0, len(hands))
+	for _, hand := range hands {
+		histories = append(histories, room.FormatPokerStars(hand.record, hand.id, userID))
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "pokerstars_"+period+".txt"))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(strings.Join(histories, "\n\n\n")))
+}
+
+// ExportOHH 下载当前用户在日期范围内的牌局历史（OHH格式，每局一个JSON对象，以空行分隔）
+// 查询参数与ExportPokerStars相同
+func (h *Handler) ExportOHH(c *gin.Context) {
+	userID, hands, period, ok := h.loadHistory(c)
+	if !ok {
+		return
+	}
+
+	histories := make([]string, 0, len(hands))
+	for _, hand := range hands {
+		data, err := json.Marshal(room.FormatOHH(hand.record, hand.id, userID))
+		if err != nil {
+			log.Printf("Skipping game %d in OHH export: %v", hand.id, err)
+			continue
+		}
+		histories = append(histories, string(data))
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "hands_"+period+".ohh"))
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(strings.Join(histories, "\n\n")))
+}
+
+// ImportOHH 导入OHH牌局（请求体为一个或多个 {"ohh": {...}} 对象），查询参数room_id为牌局所属的房间
+// 每局先用房间引擎重放，结算与OHH一致的牌局才写入games表；OHH玩家名与用户名相同的玩家关联到该用户并写入game_players，
+// 未匹配的玩家和同名的玩家不关联任何用户，该局不会出现在他们的历史导出中（结果的unlinked_players列出这些玩家）
+func (h *Handler) ImportOHH(c *gin.Context) {
+	roomID, err := strconv.ParseInt(c.Query("room_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的房间ID",
+		})
+		return
+	}
+	if _, err := models.GetRoomByID(h.db, roomID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "房间不存在",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "获取房间失败",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	var documents []room.OHHDocument
+	decoder := json.NewDecoder(c.Request.Body)
+	for {
+		var document room.OHHDocument
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "OHH格式无效",
+				"details": err.Error(),
+			})
+			return
+		}
+		documents = append(documents, document)
+	}
+	if len(documents) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "没有可导入的牌局",
+		})
+		return
+	}
+
+	imported := 0
+	results := make([]gin.H, 0, len(documents))
+	for _, document := range documents {
+		result := gin.H{"game_number": document.OHH.GameNumber}
+		results = append(results, result)
+
+		record, mismatches, err := room.ReplayOHH(&document.OHH)
+		if err != nil {
+			result["error"] = err.Error()
+			continue
+		}
+		result["mismatches"] = mismatches
+		if len(mismatches) > 0 {
+			continue
+		}
+
+		record.RoomID = roomID
+		linked := record.LinkUsers(h.matchUsers(record.Players))
+		gameLog, err := json.Marshal(record)
+		if err != nil {
+			result["error"] = err.Error()
+			continue
+		}
+		game := &models.Game{
+			RoomID:    roomID,
+			PotAmount: record.Pot,
+			StartTime: record.StartTime,
+			EndTime:   record.EndTime,
+			GameLog:   gameLog,
+		}
+		players := make([]models.GamePlayer, 0, len(linked))
+		for _, player := range linked {
+			players = append(players, models.GamePlayer{
+				UserID:      player.ID,
+				ChipsChange: player.ChipsChange,
+				Position:    player.Position,
+			})
+		}
+		if _, err := models.ImportGame(h.db, game, players); err != nil {
+			result["error"] = err.Error()
+			continue
+		}
+		result["game_id"] = game.ID
+		result["linked_players"], result["unlinked_players"] = linkedNames(record.Players)
+		imported++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imported": imported,
+		"results":  results,
+	})
+}
+
+// matchUsers 按用户名查找导入牌局中玩家对应的用户（用户名 -> 用户ID），查不到的玩家不在结果中
+func (h *Handler) matchUsers(players []room.HandPlayer) map[string]int64 {
+	userIDs := make(map[string]int64, len(players))
+	for _, player := range players {
+		user, err := models.GetUserByUsername(h.db, player.Username)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				log.Printf("Failed to match OHH player %q to a user: %v", player.Username, err)
+			}
+			continue
+		}
+		userIDs[player.Username] = user.ID
+	}
+	return userIDs
+}
+
+// linkedNames 导入牌局中关联到用户和未关联用户的玩家名
+func linkedNames(players []room.HandPlayer) ([]string, []string) {
+	linked := make([]string, 0, len(players))
+	unlinked := make([]string, 0)
+	for _, player := range players {
+		if player.ID > 0 {
+			linked = append(linked, player.Username)
+		} else {
+			unlinked = append(unlinked, player.Username)
+		}
+	}
+	return linked, unlinked
+}
+
+// loadHistory 读取当前用户在查询参数指定日期范围内的牌局记录，返回用户ID、牌局和用于文件名的日期范围；失败时直接返回错误响应
+func (h *Handler) loadHistory(c *gin.Context) (int64, []historyHand, string, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "用户未认证",
+		})
+		return 0, nil, "", false
+	}
+
+	from, to, ok := historyDateRange(c)
+	if !ok {
+		return 0, nil, "", false
+	}
+
+	// 多取一局用于判断是否超过上限，超过时拒绝导出而不是静默截断
+	games, err := models.GetUserGames(h.db, userID.(int64), from, to.AddDate(0, 0, 1), maxHistoryHands+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "获取牌局记录失败",
+			"details": err.Error(),
+		})
+		return 0, nil, "", false
+	}
+	if len(games) > maxHistoryHands {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("日期范围内的牌局超过%d局，请缩小日期范围分批导出", maxHistoryHands),
+		})
+		return 0, nil, "", false
+	}
+
+	hands := make([]historyHand, 0, len(games))
+	for _, game := range games {
+		var record room.HandRecord
+		if err := json.Unmarshal(game.GameLog, &record); err != nil {
+			log.Printf("Skipping game %d in hand history export: %v", game.ID, err)
+			continue
+		}
+		hands = append(hands, historyHand{id: game.ID, record: &record})
+	}
+	return userID.(int64), hands, from.Format("20060102") + "_" + to.Format("20060102"), true
+}
+
+// historyDateRange 解析导出的日期范围，参数无效时直接返回错误响应
+func historyDateRange(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	from := to.AddDate(0, 0, -29)
+
+	var err error
+	if value := c.Query("to"); value != "" {
+		if to, err = time.ParseInLocation(historyDateLayout, value, time.Local); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的结束日期，格式应为 YYYY-MM-DD",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+		from = to.AddDate(0, 0, -29)
+	}
+	if value := c.Query("from"); value != "" {
+		if from, err = time.ParseInLocation(historyDateLayout, value, time.Local); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的开始日期，格式应为 YYYY-MM-DD",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "开始日期不能晚于结束日期",
+		})
+		return time.Time{}, time.Time{}, false
+	}
+	if to.Sub(from) >= maxHistoryDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("日期范围不能超过%d天", maxHistoryDays),
+		})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,334 @@
+// 房间处理器
+// 作用：处理房间列表、创建房间、房间详情、加入和离开房间等HTTP请求
+
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"texas-poker-backend/internal/game/poker"
+	"texas-poker-backend/internal/game/room"
+	"texas-poker-backend/internal/models"
+	"texas-poker-backend/internal/utils"
+)
+
+// GetRooms 获取房间列表
+func (h *Handler) GetRooms(c *gin.Context) {
+	records, err := models.GetOpenRooms(h.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "获取房间列表失败",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rooms := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		rooms = append(rooms, h.liveRoom(record).GetRoomInfo())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rooms": rooms,
+	})
+}
+
+// CreateRoom 创建房间
+func (h *Handler) CreateRoom(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "用户未认证",
+		})
+		return
+	}
+
+	var req models.CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数无效",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// 校验筹码级别、盲注和密码设置
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// 创建者的筹码必须满足房间的最低要求
+	user, err := models.GetUserByID(h.db, userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "用户不存在",
+		})
+		return
+	}
+	if user.Chips < req.MinChips {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "您的筹码不足以创建这个级别的房间",
+		})
+		return
+	}
+
+	// 私人房间密码加密
+	var passwordHash string
+	if req.IsPrivate {
+		passwordHash, err = utils.HashPassword(req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "密码加密失败",
+			})
+			return
+		}
+	}
+
+	record, err := models.CreateRoom(h.db, &req, passwordHash)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "房间创建失败",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "房间创建成功",
+		"room":    h.liveRoom(record).GetRoomInfo(),
+	})
+}
+
+// GetRoom 获取房间详情
+func (h *Handler) GetRoom(c *gin.Context) {
+	record, ok := h.loadRoomRecord(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"room": h.liveRoom(record).GetRoomInfo(),
+	})
+}
+
+// JoinRoom 加入房间
+func (h *Handler) JoinRoom(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "用户未认证",
+		})
+		return
+	}
+
+	record, ok := h.loadRoomRecord(c)
+	if !ok {
+		return
+	}
+
+	// 请求体可以为空（公开房间不需要密码）
+	var req models.JoinRoomRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "请求参数无效",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	liveRoom, err := h.joinRoom(userID.(int64), record, req.Password)
+	if err != nil {
+		c.JSON(joinErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	h.afterJoin(liveRoom)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "加入房间成功",
+		"room":    liveRoom.GetRoomInfo(),
+	})
+}
+
+// LeaveRoom 离开房间
+func (h *Handler) LeaveRoom(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "用户未认证",
+		})
+		return
+	}
+
+	roomID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的房间ID",
+		})
+		return
+	}
+
+	liveRoom, exists := h.rooms.Get(roomID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "房间不存在",
+		})
+		return
+	}
+
+	if err := liveRoom.RemovePlayer(userID.(int64)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	h.afterLeave(liveRoom)
+	h.wsManager.UnsubscribeUser(userID.(int64), liveRoom.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "离开房间成功",
+	})
+}
+
+// 房间服务辅助方法
+
+// 加入房间时的业务错误
+var (
+	errRoomClosed      = errors.New("房间已关闭")
+	errWrongPassword   = errors.New("房间密码错误")
+	errAlreadyInRoom   = errors.New("您已在其他房间中，请先离开")
+	errUserUnavailable = errors.New("用户不存在或已被禁用")
+	errChipsUnsettled  = errors.New("您在上一局中的筹码尚未结算，请等该局结束后再加入")
+)
+
+// joinErrorStatus 将加入房间的错误映射为HTTP状态码
+func joinErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errWrongPassword):
+		return http.StatusForbidden
+	case errors.Is(err, errRoomClosed), errors.Is(err, errAlreadyInRoom), errors.Is(err, errChipsUnsettled):
+		return http.StatusConflict
+	case errors.Is(err, errUserUnavailable):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// joinRoom 校验房间状态、密码和筹码后让用户入座，已在房间中时直接返回房间
+// 同一用户的加入请求按顺序处理，避免并发请求都通过检查后坐进不同的房间
+func (h *Handler) joinRoom(userID int64, record *models.Room, password string) (*room.Room, error) {
+	unlock := h.rooms.LockUser(userID)
+	defer unlock()
+
+	if record.Status == "closed" {
+		return nil, errRoomClosed
+	}
+
+	liveRoom := h.liveRoom(record)
+	if liveRoom.HasPlayer(userID) {
+		return liveRoom, nil
+	}
+
+	if current, inRoom := h.rooms.FindPlayerRoom(userID); inRoom && current.ID != record.ID {
+		return nil, errAlreadyInRoom
+	}
+
+	// 离开时仍在牌局中或结算尚未写入数据库的筹码还没有反映在用户的筹码中，不能按数据库中的筹码入座
+	if h.rooms.HasUnsettledChips(userID) {
+		return nil, errChipsUnsettled
+	}
+
+	if record.IsPrivate && !utils.CheckPassword(password, record.PasswordHash) {
+		return nil, errWrongPassword
+	}
+
+	user, err := models.GetUserByID(h.db, userID)
+	if err != nil || user.Status != "active" {
+		return nil, errUserUnavailable
+	}
+
+	// 筹码、满员等检查由房间实例完成
+	if err := liveRoom.AddPlayer(user.ID, user.Username, user.Chips); err != nil {
+		return nil, err
+	}
+
+	return liveRoom, nil
+}
+
+// spectateRoom 校验房间状态和密码后返回可观战的房间实例
+func (h *Handler) spectateRoom(record *models.Room, password string) (*room.Room, error) {
+	if record.Status == "closed" {
+		return nil, errRoomClosed
+	}
+
+	if record.IsPrivate && !utils.CheckPassword(password, record.PasswordHash) {
+		return nil, errWrongPassword
+	}
+
+	return h.liveRoom(record), nil
+}
+
+// loadRoomRecord 从路径参数解析房间ID并读取房间记录，失败时直接写入响应
+func (h *Handler) loadRoomRecord(c *gin.Context) (*models.Room, bool) {
+	roomID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的房间ID",
+		})
+		return nil, false
+	}
+
+	record, err := models.GetRoomByID(h.db, roomID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "房间不存在",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "获取房间信息失败",
+				"details": err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	return record, true
+}
+
+// liveRoom 获取房间记录对应的活跃房间实例，不存在时根据记录创建并注册
+func (h *Handler) liveRoom(record *models.Room) *room.Room {
+	if liveRoom, exists := h.rooms.Get(record.ID); exists {
+		return liveRoom
+	}
+
+	liveRoom := room.NewRoom(record.ID, record.Name, record.ChipLevel, record.MinChips,
+		record.SmallBlind, record.BigBlind, record.MaxPlayers, record.IsPrivate)
+	liveRoom.CreatedAt = record.CreatedAt
+	if record.ActionTimeout > 0 {
+		liveRoom.ActionTimeout = time.Duration(record.ActionTimeout) * time.Second
+	}
+	liveRoom.TimeBank = time.Duration(record.TimeBank) * time.Second
+	if gameType, err := poker.ParseGameType(record.GameType); err == nil {
+		liveRoom.GameType = gameType
+	}
+	liveRoom.TrainingMode = record.TrainingMode
+	liveRoom.AutoStart = record.AutoStart
+	liveRoom.SetEventHandler(func(event room.Event) {
+		h.handleRoomEvent(liveRoom, event)
+	})
+	return h.rooms.Add(liveRoom)
+}
@@ -0,0 +1,184 @@
+// 牌局结算持久化
+// 作用：在后台把结束的牌局写入games和game_players并更新用户筹码，失败时按退避重试；
+// 重试用尽的牌局保留在内存中，管理员可以查看受影响的玩家并手动重试
+
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"texas-poker-backend/internal/game/room"
+	"texas-poker-backend/internal/models"
+)
+
+// 牌局持久化失败后的重试次数和首次重试前的等待时间（之后每次翻倍）
+const (
+	saveHandRetries = 5
+	saveHandBackoff = time.Second
+)
+
+// unsavedHand 重试用尽仍未持久化的牌局
+type unsavedHand struct {
+	GameID    string              `json:"game_id"`
+	RoomID    int64               `json:"room_id"`
+	PlayerIDs []int64             `json:"player_ids"` // 未结算的玩家（结算持久化前不能重新入座）
+	Players   []models.GamePlayer `json:"players"`
+	LastError string              `json:"last_error"`
+	FailedAt  time.Time           `json:"failed_at"`
+
+	game     *models.Game
+	liveRoom *room.Room
+}
+
+// unsavedHands 重试用尽的牌局（按本局ID索引）
+type unsavedHands struct {
+	hands map[string]*unsavedHand
+	mu    sync.Mutex
+}
+
+// add 记录一局重试用尽的牌局
+func (u *unsavedHands) add(hand *unsavedHand) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.hands == nil {
+		u.hands = make(map[string]*unsavedHand)
+	}
+	u.hands[hand.GameID] = hand
+}
+
+// take 取出一局未持久化的牌局，重试期间其他请求不能重复取出
+func (u *unsavedHands) take(gameID string) (*unsavedHand, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	hand, ok := u.hands[gameID]
+	delete(u.hands, gameID)
+	return hand, ok
+}
+
+// list 获取所有未持久化的牌局（按失败时间排序）
+func (u *unsavedHands) list() []*unsavedHand {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	hands := make([]*unsavedHand, 0, len(u.hands))
+	for _, hand := range u.hands {
+		hands = append(hands, hand)
+	}
+	sort.Slice(hands, func(i, j int) bool {
+		return hands[i].FailedAt.Before(hands[j].FailedAt)
+	})
+	return hands
+}
+
+// saveHand 持久化一局的记录和玩家结算，成功后清除玩家在房间中的未结算标记（在事件推送之外的goroutine中调用）
+// 写入失败时按退避重试；重试用尽后玩家保持未结算状态，不能按数据库中过期的筹码重新入座，牌局交给管理员处理
+func (h *Handler) saveHand(liveRoom *room.Room, hand *room.HandRecord) {
+	if hand == nil || len(hand.Players) == 0 {
+		return
+	}
+
+	gameLog, err := json.Marshal(hand)
+	if err != nil {
+		log.Printf("Failed to encode game log for %s: %v", hand.GameID, err)
+		return
+	}
+
+	pending := &unsavedHand{
+		GameID:    hand.GameID,
+		RoomID:    hand.RoomID,
+		PlayerIDs: make([]int64, 0, len(hand.Players)),
+		Players:   make([]models.GamePlayer, 0, len(hand.Players)),
+		game: &models.Game{
+			HandID:    hand.GameID,
+			RoomID:    hand.RoomID,
+			WinnerID:  hand.WinnerID,
+			PotAmount: hand.Pot,
+			StartTime: hand.StartTime,
+			EndTime:   hand.EndTime,
+			GameLog:   gameLog,
+		},
+		liveRoom: liveRoom,
+	}
+	for _, player := range hand.Players {
+		pending.PlayerIDs = append(pending.PlayerIDs, player.ID)
+		pending.Players = append(pending.Players, models.GamePlayer{
+			UserID:      player.ID,
+			ChipsChange: player.ChipsChange,
+			Position:    player.Position,
+		})
+	}
+
+	backoff := saveHandBackoff
+	for attempt := 0; ; attempt++ {
+		if err = h.persistHand(pending); err == nil {
+			return
+		}
+		if attempt == saveHandRetries {
+			break
+		}
+		log.Printf("Attempt %d of saving game %s in room %d failed: %v", attempt+1, hand.GameID, hand.RoomID, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	log.Printf("Giving up saving game %s in room %d; players %v stay unsettled until an admin retries: %v",
+		hand.GameID, hand.RoomID, pending.PlayerIDs, err)
+	pending.LastError = err.Error()
+	pending.FailedAt = time.Now()
+	h.unsaved.add(pending)
+}
+
+// persistHand 写入一局的记录和玩家结算，成功后清除玩家的未结算标记
+func (h *Handler) persistHand(pending *unsavedHand) error {
+	if _, err := models.SaveGame(h.db, pending.game, pending.Players); err != nil {
+		return err
+	}
+	pending.liveRoom.SettleHand(pending.PlayerIDs)
+	return nil
+}
+
+// GetUnsavedGames 查看重试用尽仍未持久化的牌局及其未结算的玩家
+func (h *Handler) GetUnsavedGames(c *gin.Context) {
+	hands := h.unsaved.list()
+	c.JSON(http.StatusOK, gin.H{
+		"games": hands,
+		"total": len(hands),
+	})
+}
+
+// RetryUnsavedGame 重新持久化一局未保存的牌局，成功后相关玩家可以重新入座
+func (h *Handler) RetryUnsavedGame(c *gin.Context) {
+	pending, ok := h.unsaved.take(c.Param("game_id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "没有该未保存的牌局",
+		})
+		return
+	}
+
+	if err := h.persistHand(pending); err != nil {
+		pending.LastError = err.Error()
+		pending.FailedAt = time.Now()
+		h.unsaved.add(pending)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "保存牌局失败",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "牌局已保存",
+		"game_id":    pending.GameID,
+		"player_ids": pending.PlayerIDs,
+	})
+}
@@ -4,12 +4,12 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	
 	"texas-poker-backend/internal/utils"
+	"texas-poker-backend/internal/websocket"
 )
 
 // WebSocketHandler 处理WebSocket连接升级
@@ -47,21 +47,21 @@ func (h *Handler) WebSocketHandler(c *gin.Context) {
 	h.wsManager.HandleWebSocket(c)
 }
 
-// BroadcastToRoom 向房间广播消息
+// BroadcastToRoom 向房间广播消息（入座玩家和观战者都会收到）
 func (h *Handler) BroadcastToRoom(roomID int64, messageType string, data interface{}) {
-	// 这里可以根据房间ID获取房间内的所有用户，然后发送消息
-	// 暂时简单实现，后续可以扩展
-	log.Printf("Broadcasting to room %d: type=%s, data=%+v", roomID, messageType, data)
+	message := websocket.Message{
+		Type:    messageType,
+		Payload: data,
+	}
+
+	h.wsManager.BroadcastToRoom(roomID, message)
 }
 
 // BroadcastToUser 向特定用户发送消息
 func (h *Handler) BroadcastToUser(userID int64, messageType string, data interface{}) {
-	message := struct {
-		Type string      `json:"type"`
-		Data interface{} `json:"data"`
-	}{
-		Type: messageType,
-		Data: data,
+	message := websocket.Message{
+		Type:    messageType,
+		Payload: data,
 	}
 
 	h.wsManager.SendToUser(userID, message)
@@ -69,12 +69,9 @@ func (h *Handler) BroadcastToUser(userID int64, messageType string, data interfa
 
 // BroadcastToAll 向所有连接的用户广播消息
 func (h *Handler) BroadcastToAll(messageType string, data interface{}) {
-	message := struct {
-		Type string      `json:"type"`
-		Data interface{} `json:"data"`
-	}{
-		Type: messageType,
-		Data: data,
+	message := websocket.Message{
+		Type:    messageType,
+		Payload: data,
 	}
 
 	h.wsManager.BroadcastToAll(message)
@@ -0,0 +1,249 @@
+// 管理员数据模型
+// 作用：定义管理员、用户管理审计日志相关的数据结构和数据库操作方法
+
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Admin 管理员模型
+type Admin struct {
+	ID        int64     `json:"id" db:"id"`
+	Username  string    `json:"username" db:"username"`
+	Email     string    `json:"email" db:"email"`
+	Password  string    `json:"-" db:"password_hash"` // 密码不返回给客户端
+	Role      string    `json:"role" db:"role"`
+	Status    string    `json:"status" db:"status"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// AdminLoginRequest 管理员登录请求结构
+type AdminLoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// AdminUpdateUserRequest 管理员更新用户请求结构
+type AdminUpdateUserRequest struct {
+	Status     string `json:"status" binding:"omitempty,oneof=active disabled"`
+	ChipsDelta int    `json:"chips_delta"`                             // 筹码调整量，可为负数
+	Reason     string `json:"reason" binding:"required,min=2,max=255"` // 操作原因，写入审计日志
+}
+
+// UserFilter 用户查询条件
+type UserFilter struct {
+	Keyword  string // 按用户名或邮箱模糊匹配
+	Status   string // 按账号状态筛选
+	Page     int    // 页码（从1开始）
+	PageSize int    // 每页数量
+}
+
+// AuditLog 管理员操作审计日志
+type AuditLog struct {
+	ID        int64     `json:"id" db:"id"`
+	AdminID   int64     `json:"admin_id" db:"admin_id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	Action    string    `json:"action" db:"action"`
+	OldValue  string    `json:"old_value" db:"old_value"`
+	NewValue  string    `json:"new_value" db:"new_value"`
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// 审计日志操作类型
+const (
+	AuditSetStatus   = "set_status"
+	AuditAdjustChips = "adjust_chips"
+)
+
+// AdminResponse 管理员响应结构（不包含敏感信息）
+type AdminResponse struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
+
+// ToResponse 将Admin转换为AdminResponse
+func (a *Admin) ToResponse() *AdminResponse {
+	return &AdminResponse{
+		ID:       a.ID,
+		Username: a.Username,
+		Email:    a.Email,
+		Role:     a.Role,
+	}
+}
+
+// GetAdminByUsername 根据用户名获取管理员
+func GetAdminByUsername(db *sql.DB, username string) (*Admin, error) {
+	admin := &Admin{}
+	query := `
+		SELECT id, username, email, password_hash, role, status, created_at
+		FROM admins WHERE username = ?
+	`
+	err := db.QueryRow(query, username).Scan(
+		&admin.ID, &admin.Username, &admin.Email, &admin.Password,
+		&admin.Role, &admin.Status, &admin.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+// SearchUsers 分页查询用户，返回当前页用户和符合条件的总数
+func SearchUsers(db *sql.DB, filter UserFilter) ([]*User, int, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+
+	if filter.Keyword != "" {
+		conditions = append(conditions, "(username LIKE ? OR email LIKE ?)")
+		pattern := "%" + filter.Keyword + "%"
+		args = append(args, pattern, pattern)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, username, email, password_hash, chips, total_games,
+		       total_wins, avatar_url, status, created_at, updated_at
+		FROM users ` + where + `
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := make([]*User, 0, filter.PageSize)
+	for rows.Next() {
+		user := &User{}
+		var avatarURL sql.NullString
+		if err := rows.Scan(
+			&user.ID, &user.Username, &user.Email, &user.Password,
+			&user.Chips, &user.TotalGames, &user.TotalWins,
+			&avatarURL, &user.Status, &user.CreatedAt, &user.UpdatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		user.AvatarURL = avatarURL.String
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
+// AdminUpdateUser 在同一事务中更新用户状态、调整筹码并写入审计日志
+func AdminUpdateUser(db *sql.DB, adminID, userID int64, req *AdminUpdateUserRequest) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var chips int
+	query := `SELECT status, chips FROM users WHERE id = ? FOR UPDATE`
+	if err := tx.QueryRow(query, userID).Scan(&status, &chips); err != nil {
+		return err
+	}
+
+	if req.Status != "" && req.Status != status {
+		if _, err := tx.Exec(`UPDATE users SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+			req.Status, userID); err != nil {
+			return err
+		}
+		if err := insertAuditLog(tx, adminID, userID, AuditSetStatus, status, req.Status, req.Reason); err != nil {
+			return err
+		}
+	}
+
+	if req.ChipsDelta != 0 {
+		newChips := chips + req.ChipsDelta
+		if newChips < 0 {
+			return fmt.Errorf("调整后筹码不能为负数（当前 %d）", chips)
+		}
+		if _, err := tx.Exec(`UPDATE users SET chips = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+			newChips, userID); err != nil {
+			return err
+		}
+		if err := insertAuditLog(tx, adminID, userID, AuditAdjustChips,
+			fmt.Sprint(chips), fmt.Sprint(newChips), req.Reason); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertAuditLog 写入一条审计日志
+func insertAuditLog(tx *sql.Tx, adminID, userID int64, action, oldValue, newValue, reason string) error {
+	query := `
+		INSERT INTO admin_audit_logs (admin_id, user_id, action, old_value, new_value, reason)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := tx.Exec(query, adminID, userID, action, oldValue, newValue, reason)
+	return err
+}
+
+// GetAuditLogsByUser 获取用户最近的审计日志
+func GetAuditLogsByUser(db *sql.DB, userID int64, limit int) ([]*AuditLog, error) {
+	query := `
+		SELECT id, admin_id, user_id, action, old_value, new_value, reason, created_at
+		FROM admin_audit_logs WHERE user_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`
+	rows, err := db.Query(query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := make([]*AuditLog, 0)
+	for rows.Next() {
+		entry := &AuditLog{}
+		if err := rows.Scan(
+			&entry.ID, &entry.AdminID, &entry.UserID, &entry.Action,
+			&entry.OldValue, &entry.NewValue, &entry.Reason, &entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		logs = append(logs, entry)
+	}
+	return logs, rows.Err()
+}
+
+// CountGames 获取历史游戏总局数
+func CountGames(db *sql.DB) (int, error) {
+	var total int
+	err := db.QueryRow(`SELECT COUNT(*) FROM games`).Scan(&total)
+	return total, err
+}
+
+// SumUserChips 获取所有用户持有的筹码总数
+func SumUserChips(db *sql.DB) (int64, error) {
+	var total sql.NullInt64
+	err := db.QueryRow(`SELECT SUM(chips) FROM users`).Scan(&total)
+	return total.Int64, err
+}
@@ -0,0 +1,26 @@
+// 胜率计算请求模型
+// 作用：定义胜率计算接口的请求参数，牌使用常见记法（如 "As"、"10h"、"A♠"），一个元素也可以是整串牌（如 "AsKd"）
+
+package models
+
+import "fmt"
+
+// EquityRequest 胜率计算请求
+type EquityRequest struct {
+	GameType   string     `json:"game_type" binding:"omitempty,oneof=holdem short plo plo8"` // 不填时为德州扑克
+	Hands      [][]string `json:"hands" binding:"required,min=2,max=10"`                     // 每手底牌（德州扑克和短牌2张、奥马哈4张）
+	Board      []string   `json:"board" binding:"omitempty,max=5"`                           // 已发出的公共牌
+	Dead       []string   `json:"dead" binding:"omitempty,max=40"`                           // 已知不会再出现的牌
+	Iterations int        `json:"iterations" binding:"omitempty,min=1,max=200000"`           // 蒙特卡洛模拟次数（奥马哈不超过MaxOmahaEquityIterations）
+}
+
+// MaxOmahaEquityIterations 奥马哈的蒙特卡洛模拟次数上限（每手底牌要评估60种组合，高低牌还要再评估低牌）
+const MaxOmahaEquityIterations = 20000
+
+// Validate 校验模拟次数不超过玩法的上限
+func (req *EquityRequest) Validate() error {
+	if (req.GameType == "plo" || req.GameType == "plo8") && req.Iterations > MaxOmahaEquityIterations {
+		return fmt.Errorf("奥马哈的模拟次数不能超过 %d", MaxOmahaEquityIterations)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// 游戏记录数据模型
+// 作用：定义牌局记录和玩家结算的数据结构，在同一事务中写入games、game_players并更新用户筹码和战绩
+
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Game 游戏记录模型
+type Game struct {
+	ID        int64           `json:"id" db:"id"`
+	HandID    string          `json:"hand_id,omitempty" db:"hand_id"` // 房间引擎生成的本局ID，导入的牌局为空
+	RoomID    int64           `json:"room_id" db:"room_id"`
+	WinnerID  int64           `json:"winner_id,omitempty" db:"winner_id"`
+	PotAmount int             `json:"pot_amount" db:"pot_amount"`
+	StartTime time.Time       `json:"start_time" db:"start_time"`
+	EndTime   time.Time       `json:"end_time" db:"end_time"`
+	GameLog   json.RawMessage `json:"game_log" db:"game_log"`
+}
+
+// GamePlayer 玩家在一局中的结算结果
+type GamePlayer struct {
+	UserID      int64 `json:"user_id" db:"user_id"`
+	ChipsChange int   `json:"chips_change" db:"chips_change"`
+	Position    int   `json:"position" db:"position"`
+}
+
+// SaveGame 在同一事务中写入牌局记录和玩家结算，并更新用户筹码、总局数和胜局数
+// 按本局ID去重：提交成功但调用方未收到结果时重试不会重复结算，直接返回已写入的牌局ID
+// 筹码变化为正的玩家记为赢得本局；结算后筹码为负说明与牌桌上的筹码不一致，整局不写入。
+// 玩家入座期间和结算完成前不允许管理员调整筹码，同一用户也不能同时坐进两个房间，正常情况下不会出现这种不一致
+func SaveGame(db *sql.DB, game *Game, players []GamePlayer) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	handID := sql.NullString{String: game.HandID, Valid: game.HandID != ""}
+	if handID.Valid {
+		var savedID int64
+		err := tx.QueryRow(`SELECT id FROM games WHERE hand_id = ? FOR UPDATE`, handID).Scan(&savedID)
+		switch {
+		case err == nil:
+			game.ID = savedID
+			return savedID, nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return 0, err
+		}
+	}
+
+	var winnerID sql.NullInt64
+	if game.WinnerID > 0 {
+		winnerID = sql.NullInt64{Int64: game.WinnerID, Valid: true}
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO games (hand_id, room_id, winner_id, pot_amount, start_time, end_time, game_log)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, handID, game.RoomID, winnerID, game.PotAmount, game.StartTime, game.EndTime, string(game.GameLog))
+	if err != nil {
+		return 0, err
+	}
+
+	gameID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, player := range players {
+		if _, err := tx.Exec(`
+			INSERT INTO game_players (game_id, user_id, chips_change, position)
+			VALUES (?, ?, ?, ?)
+		`, gameID, player.UserID, player.ChipsChange, player.Position); err != nil {
+			return 0, err
+		}
+
+		win := 0
+		if player.ChipsChange > 0 {
+			win = 1
+		}
+		result, err := tx.Exec(`
+			UPDATE users
+			SET chips = chips + ?, total_games = total_games + 1,
+			    total_wins = total_wins + ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ? AND chips + ? >= 0
+		`, player.ChipsChange, win, player.UserID, player.ChipsChange)
+		if err != nil {
+			return 0, err
+		}
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if updated == 0 {
+			return 0, fmt.Errorf("用户 %d 不存在或筹码不足以结算（变化 %d）", player.UserID, player.ChipsChange)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	game.ID = gameID
+	return gameID, nil
+}
+
+// ImportGame 在同一事务中写入导入的牌局记录（如OHH测试牌局）和匹配到用户的玩家，不改变筹码和战绩
+// 只有写入了game_players的用户能在历史导出中看到该牌局
+func ImportGame(db *sql.DB, game *Game, players []GamePlayer) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO games (room_id, pot_amount, start_time, end_time, game_log)
+		VALUES (?, ?, ?, ?, ?)
+	`, game.RoomID, game.PotAmount, game.StartTime, game.EndTime, string(game.GameLog))
+	if err != nil {
+		return 0, err
+	}
+
+	gameID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, player := range players {
+		if _, err := tx.Exec(`
+			INSERT INTO game_players (game_id, user_id, chips_change, position)
+			VALUES (?, ?, ?, ?)
+		`, gameID, player.UserID, player.ChipsChange, player.Position); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	game.ID = gameID
+	return gameID, nil
+}
+
+// gameColumns 牌局记录的查询列
+const gameColumns = `g.id, g.hand_id, g.room_id, g.winner_id, g.pot_amount, g.start_time, g.end_time, g.game_log`
+
+// GetGameByID 根据ID获取牌局记录
+func GetGameByID(db *sql.DB, id int64) (*Game, error) {
+	return scanGame(db.QueryRow(`SELECT `+gameColumns+` FROM games g WHERE g.id = ?`, id))
+}
+
+// GetUserGames 获取用户在[from, to)时间范围内参与的牌局（按开始时间排序，最多limit局）
+func GetUserGames(db *sql.DB, userID int64, from, to time.Time, limit int) ([]*Game, error) {
+	rows, err := db.Query(`
+		SELECT `+gameColumns+`
+		FROM games g
+		JOIN game_players gp ON gp.game_id = g.id
+		WHERE gp.user_id = ? AND g.start_time >= ? AND g.start_time < ?
+		ORDER BY g.start_time, g.id
+		LIMIT ?
+	`, userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	games := make([]*Game, 0)
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+	return games, rows.Err()
+}
+
+// scanGame 扫描一行牌局记录
+func scanGame(row rowScanner) (*Game, error) {
+	game := &Game{}
+	var handID sql.NullString
+	var winnerID sql.NullInt64
+	var endTime sql.NullTime
+	var gameLog []byte
+
+	err := row.Scan(&game.ID, &handID, &game.RoomID, &winnerID, &game.PotAmount, &game.StartTime, &endTime, &gameLog)
+	if err != nil {
+		return nil, err
+	}
+
+	game.HandID = handID.String
+	game.WinnerID = winnerID.Int64
+	game.EndTime = endTime.Time
+	game.GameLog = gameLog
+	return game, nil
+}
@@ -0,0 +1,217 @@
+// 房间数据模型
+// 作用：定义房间相关的数据结构、请求参数、筹码级别规则和数据库操作方法
+
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Room 房间模型
+type Room struct {
+	ID            int64     `json:"id" db:"id"`
+	Name          string    `json:"name" db:"name"`
+	ChipLevel     string    `json:"chip_level" db:"chip_level"`
+	MinChips      int       `json:"min_chips" db:"min_chips"`
+	SmallBlind    int       `json:"small_blind" db:"small_blind"`
+	BigBlind      int       `json:"big_blind" db:"big_blind"`
+	MaxPlayers    int       `json:"max_players" db:"max_players"`
+	IsPrivate     bool      `json:"is_private" db:"is_private"`
+	PasswordHash  string    `json:"-" db:"password_hash"`               // 密码不返回给客户端
+	ActionTimeout int       `json:"action_timeout" db:"action_timeout"` // 每次操作的时限（秒）
+	TimeBank      int       `json:"time_bank" db:"time_bank"`           // 每位玩家入座期间可用的额外思考时间（秒），0表示不启用
+	GameType      string    `json:"game_type" db:"game_type"`           // 玩法（holdem/short/plo/plo8）
+	TrainingMode  bool      `json:"training_mode" db:"training_mode"`   // 训练模式（向玩家实时显示听牌和补牌）
+	AutoStart     bool      `json:"auto_start" db:"auto_start"`         // 有足够玩家时自动开始下一局（否则需要玩家发送start_game）
+	Status        string    `json:"status" db:"status"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateRoomRequest 创建房间请求结构
+type CreateRoomRequest struct {
+	Name          string `json:"name" binding:"required,min=2,max=50"`
+	ChipLevel     string `json:"chip_level" binding:"required,oneof=low medium high"`
+	MinChips      int    `json:"min_chips" binding:"required,min=1"`
+	SmallBlind    int    `json:"small_blind" binding:"required,min=1"`
+	BigBlind      int    `json:"big_blind" binding:"required,min=1"`
+	MaxPlayers    int    `json:"max_players" binding:"required,min=2,max=6"`
+	IsPrivate     bool   `json:"is_private"`
+	Password      string `json:"password"`
+	ActionTimeout int    `json:"action_timeout" binding:"omitempty,min=5,max=120"` // 不填时使用默认时限
+	TimeBank      int    `json:"time_bank" binding:"omitempty,min=0,max=300"`
+	GameType      string `json:"game_type" binding:"omitempty,oneof=holdem short plo plo8"` // 不填时为无限注德州扑克
+	TrainingMode  bool   `json:"training_mode"`
+	AutoStart     bool   `json:"auto_start"`
+}
+
+// DefaultActionTimeout 默认的每次操作时限（秒）
+const DefaultActionTimeout = 30
+
+// DefaultGameType 默认玩法（无限注德州扑克）
+const DefaultGameType = "holdem"
+
+// roomColumns 房间查询的字段列表
+const roomColumns = `id, name, chip_level, min_chips, small_blind, big_blind,
+		       max_players, is_private, password_hash, action_timeout, time_bank, game_type, training_mode, auto_start, status, created_at`
+
+// JoinRoomRequest 加入房间请求结构（请求体可为空）
+type JoinRoomRequest struct {
+	Password string `json:"password"`
+}
+
+// ChipLevelRule 筹码级别规则
+type ChipLevelRule struct {
+	MinChips      int // 该级别允许的最低入场筹码
+	MinSmallBlind int // 该级别允许的最低小盲注
+}
+
+// ChipLevelRules 各筹码级别的规则（与前端创建房间的默认值保持一致）
+var ChipLevelRules = map[string]ChipLevelRule{
+	"low":    {MinChips: 100, MinSmallBlind: 1},
+	"medium": {MinChips: 500, MinSmallBlind: 25},
+	"high":   {MinChips: 2000, MinSmallBlind: 100},
+}
+
+// Validate 校验创建房间请求的筹码级别、盲注和密码设置
+func (req *CreateRoomRequest) Validate() error {
+	rule, ok := ChipLevelRules[req.ChipLevel]
+	if !ok {
+		return fmt.Errorf("无效的筹码级别: %s", req.ChipLevel)
+	}
+
+	if req.MinChips < rule.MinChips {
+		return fmt.Errorf("该级别最低筹码要求不能少于 %d", rule.MinChips)
+	}
+
+	if req.SmallBlind < rule.MinSmallBlind {
+		return fmt.Errorf("该级别小盲注不能少于 %d", rule.MinSmallBlind)
+	}
+
+	if req.BigBlind < req.SmallBlind*2 {
+		return fmt.Errorf("大盲注不能少于小盲注的2倍")
+	}
+
+	if req.MinChips < req.BigBlind*10 {
+		return fmt.Errorf("最低筹码不能少于大盲注的10倍 (%d)", req.BigBlind*10)
+	}
+
+	if req.IsPrivate && len(req.Password) < 4 {
+		return fmt.Errorf("私人房间密码至少4个字符")
+	}
+
+	return nil
+}
+
+// CreateRoom 创建房间
+func CreateRoom(db *sql.DB, req *CreateRoomRequest, passwordHash string) (*Room, error) {
+	query := `
+		INSERT INTO rooms (name, chip_level, min_chips, small_blind, big_blind,
+		                   max_players, is_private, password_hash, action_timeout, time_bank, game_type, training_mode, auto_start)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	var hash sql.NullString
+	if passwordHash != "" {
+		hash = sql.NullString{String: passwordHash, Valid: true}
+	}
+
+	actionTimeout := req.ActionTimeout
+	if actionTimeout == 0 {
+		actionTimeout = DefaultActionTimeout
+	}
+
+	gameType := req.GameType
+	if gameType == "" {
+		gameType = DefaultGameType
+	}
+
+	result, err := db.Exec(query, req.Name, req.ChipLevel, req.MinChips, req.SmallBlind,
+		req.BigBlind, req.MaxPlayers, req.IsPrivate, hash, actionTimeout, req.TimeBank, gameType, req.TrainingMode, req.AutoStart)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRoomByID(db, id)
+}
+
+// GetRoomByID 根据ID获取房间
+func GetRoomByID(db *sql.DB, id int64) (*Room, error) {
+	query := `
+		SELECT ` + roomColumns + `
+		FROM rooms WHERE id = ?
+	`
+	return scanRoom(db.QueryRow(query, id))
+}
+
+// GetOpenRooms 获取所有未关闭的房间
+func GetOpenRooms(db *sql.DB) ([]*Room, error) {
+	query := `
+		SELECT ` + roomColumns + `
+		FROM rooms WHERE status <> 'closed'
+		ORDER BY created_at DESC
+	`
+	return queryRooms(db, query)
+}
+
+// GetAllRooms 获取所有房间（包括已关闭的房间）
+func GetAllRooms(db *sql.DB) ([]*Room, error) {
+	query := `
+		SELECT ` + roomColumns + `
+		FROM rooms
+		ORDER BY created_at DESC
+	`
+	return queryRooms(db, query)
+}
+
+// UpdateRoomStatus 更新房间状态
+func UpdateRoomStatus(db *sql.DB, id int64, status string) error {
+	query := `UPDATE rooms SET status = ? WHERE id = ?`
+	_, err := db.Exec(query, status, id)
+	return err
+}
+
+// rowScanner 统一sql.Row和sql.Rows的Scan方法
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRoom 扫描一行房间数据
+func scanRoom(row rowScanner) (*Room, error) {
+	room := &Room{}
+	var passwordHash sql.NullString
+	err := row.Scan(
+		&room.ID, &room.Name, &room.ChipLevel, &room.MinChips,
+		&room.SmallBlind, &room.BigBlind, &room.MaxPlayers, &room.IsPrivate,
+		&passwordHash, &room.ActionTimeout, &room.TimeBank, &room.GameType, &room.TrainingMode, &room.AutoStart, &room.Status, &room.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	room.PasswordHash = passwordHash.String
+	return room, nil
+}
+
+// queryRooms 执行查询并返回房间列表
+func queryRooms(db *sql.DB, query string, args ...interface{}) ([]*Room, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := make([]*Room, 0)
+	for rows.Next() {
+		room, err := scanRoom(rows)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+	return rooms, rows.Err()
+}
@@ -15,12 +15,27 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-// Message WebSocket消息结构
+// Message WebSocket消息结构（服务端推送，前端读取payload字段）
 type Message struct {
-	Type string      `json:"type"`
-	Data interface{} `json:"data"`
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload,omitempty"`
 }
 
+// ClientMessage 客户端发送的消息结构
+type ClientMessage struct {
+	Type     string          `json:"type"`
+	RoomID   json.RawMessage `json:"room_id,omitempty"` // 前端可能以字符串或数字发送
+	Action   string          `json:"action,omitempty"`
+	Amount   int             `json:"amount,omitempty"`
+	Password string          `json:"password,omitempty"`
+	Spectate bool            `json:"spectate,omitempty"` // 以观战者身份订阅房间
+	Seed     string          `json:"seed,omitempty"`     // 参与洗牌的客户端种子
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+// MessageHandler 客户端业务消息处理函数
+type MessageHandler func(client *Client, msg ClientMessage)
+
 // Client WebSocket客户端结构
 type Client struct {
 	ID     string          // 客户端唯一标识
@@ -28,6 +43,8 @@ type Client struct {
 	Conn   *websocket.Conn // WebSocket连接
 	Send   chan Message    // 发送消息通道
 	Hub    *Hub            // 所属的Hub
+
+	rooms map[int64]bool // 已订阅的房间（由Hub的互斥锁保护）
 }
 
 // Hub WebSocket连接中心
@@ -50,6 +67,12 @@ type Hub struct {
 	// 用户ID到客户端的映射
 	userClients map[int64]*Client
 
+	// 房间ID到订阅客户端的映射（入座玩家和观战者相同处理）
+	roomClients map[int64]map[*Client]bool
+
+	// 业务消息处理函数
+	handler MessageHandler
+
 	// 互斥锁
 	mu sync.RWMutex
 }
@@ -75,6 +98,7 @@ func NewManager() *Manager {
 		broadcast:    make(chan Message),
 		roomMessages: make(chan RoomMessage),
 		userClients:  make(map[int64]*Client),
+		roomClients:  make(map[int64]map[*Client]bool),
 	}
 
 	// 启动Hub
@@ -109,42 +133,73 @@ func (h *Hub) run() {
 			// 发送连接成功消息
 			client.Send <- Message{
 				Type: "connected",
-				Data: map[string]interface{}{
+				Payload: map[string]interface{}{
 					"client_id": client.ID,
 					"message":   "WebSocket连接成功",
 				},
 			}
 
 		case client := <-h.unregister:
-			// 注销客户端
+			// 注销客户端（同时清理房间订阅）
 			h.mu.Lock()
-			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
-				delete(h.userClients, client.UserID)
-				close(client.Send)
-			}
+			h.removeClient(client)
 			h.mu.Unlock()
 			
 			log.Printf("Client %s (UserID: %d) disconnected", client.ID, client.UserID)
 
 		case message := <-h.broadcast:
 			// 广播消息给所有客户端
-			h.mu.RLock()
+			h.mu.Lock()
 			for client := range h.clients {
 				select {
 				case client.Send <- message:
 				default:
 					// 客户端发送通道已满，断开连接
-					delete(h.clients, client)
-					delete(h.userClients, client.UserID)
-					close(client.Send)
+					h.removeClient(client)
 				}
 			}
-			h.mu.RUnlock()
+			h.mu.Unlock()
 
 		case roomMsg := <-h.roomMessages:
-			// 发送房间消息（后续实现房间功能时使用）
-			log.Printf("Room message for room %d: %+v", roomMsg.RoomID, roomMsg.Message)
+			// 只发送给订阅了该房间的客户端
+			h.mu.Lock()
+			for client := range h.roomClients[roomMsg.RoomID] {
+				select {
+				case client.Send <- roomMsg.Message:
+				default:
+					// 客户端发送通道已满，断开连接
+					h.removeClient(client)
+				}
+			}
+			h.mu.Unlock()
+		}
+	}
+}
+
+// removeClient 移除客户端及其所有房间订阅（调用方需持有写锁）
+func (h *Hub) removeClient(client *Client) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+
+	for roomID := range client.rooms {
+		h.leaveRoom(client, roomID)
+	}
+	delete(h.clients, client)
+	// 同一用户可能已建立新连接，只删除指向当前客户端的映射
+	if h.userClients[client.UserID] == client {
+		delete(h.userClients, client.UserID)
+	}
+	close(client.Send)
+}
+
+// leaveRoom 取消客户端的房间订阅（调用方需持有写锁）
+func (h *Hub) leaveRoom(client *Client, roomID int64) {
+	delete(client.rooms, roomID)
+	if members, ok := h.roomClients[roomID]; ok {
+		delete(members, client)
+		if len(members) == 0 {
+			delete(h.roomClients, roomID)
 		}
 	}
 }
@@ -172,6 +227,7 @@ func (m *Manager) HandleWebSocket(c *gin.Context) {
 		Conn:   conn,
 		Send:   make(chan Message, 256),
 		Hub:    m.Hub,
+		rooms:  make(map[int64]bool),
 	}
 
 	// 注册客户端
@@ -197,7 +253,7 @@ func (c *Client) readPump() {
 	})
 
 	for {
-		var msg Message
+		var msg ClientMessage
 		err := c.Conn.ReadJSON(&msg)
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
@@ -243,26 +299,60 @@ func (c *Client) writePump() {
 }
 
 // handleMessage 处理客户端消息
-func (c *Client) handleMessage(msg Message) {
+func (c *Client) handleMessage(msg ClientMessage) {
 	log.Printf("Received message from client %s: type=%s", c.ID, msg.Type)
 
 	switch msg.Type {
 	case "ping":
 		// 心跳响应
-		c.Send <- Message{
-			Type: "pong",
-			Data: time.Now().Unix(),
+		c.SendMessage(Message{
+			Type:    "pong",
+			Payload: time.Now().Unix(),
+		})
+
+	case "heartbeat":
+		// 前端心跳
+		c.SendMessage(Message{
+			Type:    "heartbeat",
+			Payload: time.Now().Unix(),
+		})
+
+	default:
+		// 游戏相关消息交给业务处理函数
+		if c.Hub.handler != nil {
+			c.Hub.handler(c, msg)
+			return
 		}
+		log.Printf("Unknown message type: %s", msg.Type)
+	}
+}
+
+// SendMessage 向客户端发送消息，发送通道已满时丢弃
+// 持有Hub的读锁并确认客户端仍已注册，避免Hub同时注销客户端并关闭其发送通道
+func (c *Client) SendMessage(msg Message) {
+	c.Hub.mu.RLock()
+	defer c.Hub.mu.RUnlock()
 
-	case "game_action":
-		// 游戏操作消息（后续实现游戏逻辑时处理）
-		log.Printf("Game action from user %d: %+v", c.UserID, msg.Data)
+	if _, ok := c.Hub.clients[c]; !ok {
+		return
+	}
+	c.trySend(msg)
+}
 
+// trySend 向客户端的发送通道写入消息，通道已满时丢弃（调用方需持有Hub的锁，并确认客户端仍已注册）
+func (c *Client) trySend(msg Message) {
+	select {
+	case c.Send <- msg:
 	default:
-		log.Printf("Unknown message type: %s", msg.Type)
+		log.Printf("Failed to send message to client %s: channel full", c.ID)
 	}
 }
 
+// SetMessageHandler 设置业务消息处理函数（需在接受连接前调用）
+func (m *Manager) SetMessageHandler(handler MessageHandler) {
+	m.Hub.handler = handler
+}
+
 // BroadcastToAll 广播消息给所有客户端
 func (m *Manager) BroadcastToAll(msg Message) {
 	m.Hub.broadcast <- msg
@@ -271,9 +361,10 @@ func (m *Manager) BroadcastToAll(msg Message) {
 // SendToUser 发送消息给特定用户
 func (m *Manager) SendToUser(userID int64, msg Message) {
 	m.Hub.mu.RLock()
-	client, exists := m.Hub.userClients[userID]
-	m.Hub.mu.RUnlock()
+	defer m.Hub.mu.RUnlock()
 
+	// 持有读锁发送，避免Hub同时注销客户端并关闭其发送通道
+	client, exists := m.Hub.userClients[userID]
 	if exists {
 		select {
 		case client.Send <- msg:
@@ -283,6 +374,89 @@ func (m *Manager) SendToUser(userID int64, msg Message) {
 	}
 }
 
+// Subscribe 订阅房间消息（入座玩家和观战者使用相同的订阅）
+func (m *Manager) Subscribe(client *Client, roomID int64) {
+	m.Hub.mu.Lock()
+	defer m.Hub.mu.Unlock()
+
+	// 已注销的客户端不再订阅
+	if _, ok := m.Hub.clients[client]; !ok {
+		return
+	}
+
+	members, ok := m.Hub.roomClients[roomID]
+	if !ok {
+		members = make(map[*Client]bool)
+		m.Hub.roomClients[roomID] = members
+	}
+	members[client] = true
+	client.rooms[roomID] = true
+}
+
+// Unsubscribe 取消订阅房间消息
+func (m *Manager) Unsubscribe(client *Client, roomID int64) {
+	m.Hub.mu.Lock()
+	defer m.Hub.mu.Unlock()
+
+	m.Hub.leaveRoom(client, roomID)
+}
+
+// UnsubscribeUser 取消用户当前连接对房间的订阅
+func (m *Manager) UnsubscribeUser(userID, roomID int64) {
+	m.Hub.mu.Lock()
+	defer m.Hub.mu.Unlock()
+
+	if client, ok := m.Hub.userClients[userID]; ok {
+		m.Hub.leaveRoom(client, roomID)
+	}
+}
+
+// SubscribedRooms 获取客户端已订阅的房间ID列表
+func (m *Manager) SubscribedRooms(client *Client) []int64 {
+	m.Hub.mu.RLock()
+	defer m.Hub.mu.RUnlock()
+
+	roomIDs := make([]int64, 0, len(client.rooms))
+	for roomID := range client.rooms {
+		roomIDs = append(roomIDs, roomID)
+	}
+	return roomIDs
+}
+
+// BroadcastToRoom 向订阅了房间的所有客户端广播消息
+func (m *Manager) BroadcastToRoom(roomID int64, msg Message) {
+	m.Hub.roomMessages <- RoomMessage{
+		RoomID:  roomID,
+		Message: msg,
+	}
+}
+
+// SendToRoomEach 为房间的每个订阅者单独生成并发送消息（用于按观察者视角推送）
+// 消息在锁外生成；发送时持有读锁，避免Hub在发送期间注销客户端并关闭其发送通道
+func (m *Manager) SendToRoomEach(roomID int64, build func(userID int64) Message) {
+	m.Hub.mu.RLock()
+	userIDs := make(map[int64]bool, len(m.Hub.roomClients[roomID]))
+	for client := range m.Hub.roomClients[roomID] {
+		userIDs[client.UserID] = true
+	}
+	m.Hub.mu.RUnlock()
+
+	messages := make(map[int64]Message, len(userIDs))
+	for userID := range userIDs {
+		messages[userID] = build(userID)
+	}
+
+	m.Hub.mu.RLock()
+	defer m.Hub.mu.RUnlock()
+
+	// 生成消息期间新订阅的客户端等下一次推送
+	for client := range m.Hub.roomClients[roomID] {
+		if msg, ok := messages[client.UserID]; ok {
+			client.trySend(msg)
+		}
+	}
+}
+
 // GetConnectedUsers 获取当前连接的用户列表
 func (m *Manager) GetConnectedUsers() []int64 {
 	m.Hub.mu.RLock()
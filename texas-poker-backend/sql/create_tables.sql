@@ -34,6 +34,11 @@ CREATE TABLE rooms (
     max_players INT DEFAULT 6 COMMENT '最大玩家数',
     is_private BOOLEAN DEFAULT FALSE COMMENT '是否私人房间',
     password_hash VARCHAR(255) COMMENT '私人房间密码哈希',
+    action_timeout INT DEFAULT 30 COMMENT '每次操作时限（秒）',
+    time_bank INT DEFAULT 0 COMMENT '每位玩家的时间银行（秒），0表示不启用',
+    game_type VARCHAR(20) NOT NULL DEFAULT 'holdem' COMMENT '玩法（holdem: 无限注德州扑克, short: 无限注短牌德州扑克, plo: 底池限注奥马哈, plo8: 底池限注奥马哈高低牌）',
+    training_mode BOOLEAN DEFAULT FALSE COMMENT '训练模式（向玩家实时显示听牌和补牌）',
+    auto_start BOOLEAN DEFAULT FALSE COMMENT '有足够玩家时自动开始下一局',
     status ENUM('waiting', 'playing', 'closed') DEFAULT 'waiting' COMMENT '房间状态',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     INDEX idx_chip_level (chip_level),
@@ -44,6 +49,7 @@ CREATE TABLE rooms (
 -- 游戏记录表
 CREATE TABLE games (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    hand_id VARCHAR(64) NULL COMMENT '本局ID（房间引擎生成，结算重试时去重；导入的牌局为空）',
     room_id BIGINT NOT NULL COMMENT '所属房间ID',
     winner_id BIGINT COMMENT '获胜者ID',
     pot_amount INT NOT NULL COMMENT '底池金额',
@@ -54,7 +60,8 @@ CREATE TABLE games (
     FOREIGN KEY (winner_id) REFERENCES users(id) ON DELETE SET NULL,
     INDEX idx_room_id (room_id),
     INDEX idx_winner_id (winner_id),
-    INDEX idx_start_time (start_time)
+    INDEX idx_start_time (start_time),
+    UNIQUE KEY uk_hand_id (hand_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='游戏记录表';
 
 -- 游戏玩家表
@@ -85,6 +92,23 @@ CREATE TABLE admins (
     INDEX idx_status (status)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='管理员表';
 
+-- 管理员操作审计日志表
+CREATE TABLE admin_audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    admin_id BIGINT NOT NULL COMMENT '操作管理员ID',
+    user_id BIGINT NOT NULL COMMENT '被操作用户ID',
+    action VARCHAR(50) NOT NULL COMMENT '操作类型',
+    old_value VARCHAR(255) COMMENT '修改前的值',
+    new_value VARCHAR(255) COMMENT '修改后的值',
+    reason VARCHAR(255) NOT NULL COMMENT '操作原因',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '操作时间',
+    FOREIGN KEY (admin_id) REFERENCES admins(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_admin_id (admin_id),
+    INDEX idx_user_id (user_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='管理员操作审计日志表';
+
 -- 插入默认管理员账号
 INSERT INTO admins (username, email, password_hash, role) VALUES 
 ('admin', 'admin@texaspoker.com', '$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi', 'super'); 
\ No newline at end of file
@@ -0,0 +1,43 @@
+-- 德州扑克数据库升级脚本
+-- 作用：为已按旧版create_tables.sql建库的数据库补充新增的表和字段（每段只需执行一次，新建库直接使用create_tables.sql）
+
+USE texas_poker;
+
+-- 管理员操作审计日志表
+CREATE TABLE IF NOT EXISTS admin_audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    admin_id BIGINT NOT NULL COMMENT '操作管理员ID',
+    user_id BIGINT NOT NULL COMMENT '被操作用户ID',
+    action VARCHAR(50) NOT NULL COMMENT '操作类型',
+    old_value VARCHAR(255) COMMENT '修改前的值',
+    new_value VARCHAR(255) COMMENT '修改后的值',
+    reason VARCHAR(255) NOT NULL COMMENT '操作原因',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '操作时间',
+    FOREIGN KEY (admin_id) REFERENCES admins(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_admin_id (admin_id),
+    INDEX idx_user_id (user_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='管理员操作审计日志表';
+
+-- 房间操作时限和时间银行
+ALTER TABLE rooms
+    ADD COLUMN action_timeout INT DEFAULT 30 COMMENT '每次操作时限（秒）' AFTER password_hash,
+    ADD COLUMN time_bank INT DEFAULT 0 COMMENT '每位玩家的时间银行（秒），0表示不启用' AFTER action_timeout;
+
+-- 房间玩法
+ALTER TABLE rooms
+    ADD COLUMN game_type VARCHAR(20) NOT NULL DEFAULT 'holdem' COMMENT '玩法（holdem: 无限注德州扑克, short: 无限注短牌德州扑克, plo: 底池限注奥马哈, plo8: 底池限注奥马哈高低牌）' AFTER time_bank;
+
+-- 房间训练模式
+ALTER TABLE rooms
+    ADD COLUMN training_mode BOOLEAN DEFAULT FALSE COMMENT '训练模式（向玩家实时显示听牌和补牌）' AFTER game_type;
+
+-- 房间自动开局
+ALTER TABLE rooms
+    ADD COLUMN auto_start BOOLEAN DEFAULT FALSE COMMENT '有足够玩家时自动开始下一局' AFTER training_mode;
+
+-- 牌局记录按本局ID去重
+ALTER TABLE games
+    ADD COLUMN hand_id VARCHAR(64) NULL COMMENT '本局ID（房间引擎生成，结算重试时去重；导入的牌局为空）' AFTER id,
+    ADD UNIQUE KEY uk_hand_id (hand_id);